@@ -0,0 +1,258 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cfntypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+)
+
+const (
+	// The performance validator stores its results under this service and table
+	performanceService  = "AmazonCloudWatchAgent"
+	performanceDatabase = "CWAPerformanceMetrics"
+)
+
+var (
+	dryRun                   = flag.Bool("dryRun", true, "Only list the leaked resources. Set to false to delete them.")
+	olderThan                = flag.Duration("olderThan", 24*time.Hour, "Only resources not used for this long are leaked.")
+	logGroupPrefixes         = flag.String("logGroupPrefixes", "emf-test-group-", "Comma separated log group name prefixes used by the tests.")
+	ssmParameterPrefixes     = flag.String("ssmParameterPrefixes", "WindowsAgentConfigSSMTest-,cwagent-integ-test-ssm-config-", "Comma separated SSM parameter name prefixes used by the tests.")
+	stackPrefixes            = flag.String("stackPrefixes", "cfTestStack", "Comma separated CloudFormation stack name prefixes used by the tests.")
+	dynamodbTable            = flag.String("dynamodbTable", performanceDatabase, "DynamoDB table with the performance results.")
+	dynamodbUniqueIdPrefixes = flag.String("dynamodbUniqueIdPrefixes", "", "Comma separated UniqueID prefixes of the DynamoDB items to clean up. Empty by default since the performance results are kept on purpose.")
+	allowlist                = flag.String("allowlist", "", "Comma separated name patterns (e.g MetricRenameSSM or emf-test-group-keep*) which are never deleted.")
+)
+
+// leakedResource matches the naming prefix of a test and has not been used since the cutoff
+type leakedResource struct {
+	kind     string
+	name     string
+	lastUsed time.Time
+	delete   func() error
+}
+
+func main() {
+	flag.Parse()
+	cutoff := time.Now().Add(-*olderThan)
+	allowed := splitList(*allowlist)
+
+	finders := []func(time.Time) ([]leakedResource, error){
+		findLogGroups,
+		findSSMParameters,
+		findStacks,
+		findDatabaseItems,
+	}
+	var resources []leakedResource
+	for _, find := range finders {
+		found, err := find(cutoff)
+		if err != nil {
+			log.Fatalf("Failed to list the leaked resources: %v", err)
+		}
+		for _, resource := range found {
+			if isAllowed(resource.name, allowed) {
+				log.Printf("Skipping allowlisted %s %s", resource.kind, resource.name)
+				continue
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tLAST USED\t")
+	for _, resource := range resources {
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", resource.kind, resource.name, resource.lastUsed.UTC().Format(time.RFC3339))
+	}
+	w.Flush()
+
+	if *dryRun {
+		fmt.Printf("Found %d leaked resource(s) not used since %s. Run with -dryRun=false to delete them.\n", len(resources), cutoff.UTC().Format(time.RFC3339))
+		return
+	}
+
+	var failed int
+	for _, resource := range resources {
+		log.Printf("Deleting %s %s", resource.kind, resource.name)
+		if err := resource.delete(); err != nil {
+			log.Printf("Failed to delete %s %s: %v", resource.kind, resource.name, err)
+			failed++
+		}
+	}
+	fmt.Printf("Deleted %d of %d leaked resource(s)\n", len(resources)-failed, len(resources))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// findLogGroups returns the log groups created before the cutoff without log events since then
+func findLogGroups(cutoff time.Time) ([]leakedResource, error) {
+	var resources []leakedResource
+	for _, prefix := range splitList(*logGroupPrefixes) {
+		logGroups, err := awsservice.ListLogGroups(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, logGroup := range logGroups {
+			lastUsed := time.UnixMilli(aws.ToInt64(logGroup.CreationTime))
+			// Log groups without stored bytes have no log stream to look at
+			if aws.ToInt64(logGroup.StoredBytes) > 0 {
+				for _, logStream := range awsservice.GetLogStreams(*logGroup.LogGroupName) {
+					if lastEvent := time.UnixMilli(aws.ToInt64(logStream.LastEventTimestamp)); lastEvent.After(lastUsed) {
+						lastUsed = lastEvent
+					}
+				}
+			}
+			if lastUsed.After(cutoff) {
+				continue
+			}
+			logGroupName := *logGroup.LogGroupName
+			resources = append(resources, leakedResource{
+				kind:     "log group",
+				name:     logGroupName,
+				lastUsed: lastUsed,
+				delete: func() error {
+					return awsservice.DeleteLogGroupWithError(logGroupName)
+				},
+			})
+		}
+	}
+	return resources, nil
+}
+
+// findSSMParameters returns the SSM parameters last modified before the cutoff
+func findSSMParameters(cutoff time.Time) ([]leakedResource, error) {
+	var resources []leakedResource
+	for _, prefix := range splitList(*ssmParameterPrefixes) {
+		parameters, err := awsservice.ListParameters(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, parameter := range parameters {
+			lastUsed := aws.ToTime(parameter.LastModifiedDate)
+			if lastUsed.After(cutoff) {
+				continue
+			}
+			parameterName := *parameter.Name
+			resources = append(resources, leakedResource{
+				kind:     "ssm parameter",
+				name:     parameterName,
+				lastUsed: lastUsed,
+				delete: func() error {
+					return awsservice.DeleteParameter(parameterName)
+				},
+			})
+		}
+	}
+	return resources, nil
+}
+
+// findStacks returns the CloudFormation stacks created before the cutoff which are not being deleted already
+func findStacks(cutoff time.Time) ([]leakedResource, error) {
+	prefixes := splitList(*stackPrefixes)
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+	ctx := context.Background()
+	stacks, err := awsservice.ListStacks(ctx, awsservice.CloudformationClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []leakedResource
+	for _, stack := range stacks {
+		stackName := aws.ToString(stack.StackName)
+		if !hasAnyPrefix(stackName, prefixes) || stack.StackStatus == cfntypes.StackStatusDeleteInProgress {
+			continue
+		}
+		lastUsed := aws.ToTime(stack.CreationTime)
+		if lastUsed.After(cutoff) {
+			continue
+		}
+		resources = append(resources, leakedResource{
+			kind:     "cloudformation stack",
+			name:     stackName,
+			lastUsed: lastUsed,
+			delete: func() error {
+				return awsservice.DeleteStackWithError(ctx, stackName, awsservice.CloudformationClient)
+			},
+		})
+	}
+	return resources, nil
+}
+
+// findDatabaseItems returns the performance results committed before the cutoff
+func findDatabaseItems(cutoff time.Time) ([]leakedResource, error) {
+	var resources []leakedResource
+	for _, prefix := range splitList(*dynamodbUniqueIdPrefixes) {
+		items, err := awsservice.ScanItemsInDatabase(*dynamodbTable, "UniqueID", prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			service, _ := item["Service"].(string)
+			uniqueID, _ := item["UniqueID"].(string)
+			commitDate, _ := item["CommitDate"].(float64)
+			if service != performanceService {
+				continue
+			}
+			lastUsed := time.Unix(int64(commitDate), 0)
+			if lastUsed.After(cutoff) {
+				continue
+			}
+			resources = append(resources, leakedResource{
+				kind:     "dynamodb item",
+				name:     uniqueID,
+				lastUsed: lastUsed,
+				delete: func() error {
+					return awsservice.DeleteItemInDatabase(*dynamodbTable, map[string]interface{}{
+						"Service":  service,
+						"UniqueID": uniqueID,
+					})
+				},
+			})
+		}
+	}
+	return resources, nil
+}
+
+// splitList drops the empty entries so an empty prefix never matches every resource
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowed(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentcontrollertype
+
+import "strings"
+
+type AgentControllerType string
+
+const (
+	// CTL manages the agent installed by the package with amazon-cloudwatch-agent-ctl
+	CTL AgentControllerType = "CTL"
+	// BINARY runs the agent and config translator binaries directly
+	BINARY AgentControllerType = "BINARY"
+	// DOCKER runs the agent image in a local container
+	DOCKER AgentControllerType = "DOCKER"
+)
+
+var (
+	agentControllerTypes = map[string]AgentControllerType{
+		"CTL":    CTL,
+		"BINARY": BINARY,
+		"DOCKER": DOCKER,
+	}
+)
+
+func FromString(str string) (AgentControllerType, bool) {
+	c, ok := agentControllerTypes[strings.ToUpper(str)]
+	return c, ok
+}
@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionsampling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Mode string
+
+const (
+	// All validates every dimension set
+	All Mode = "all"
+	// First validates the first Count dimension sets in sorted order
+	First Mode = "first"
+	// Stratified validates the first Count dimension sets of each value of the Key dimension
+	Stratified Mode = "stratified"
+	// Random validates Count dimension sets picked with Seed
+	Random Mode = "random"
+)
+
+// Policy picks the dimension sets of a metric to validate. It is written as all, first:<count>,
+// stratified:<key>[:<count>] or random:<count>[:<seed>].
+type Policy struct {
+	Mode  Mode
+	Count int
+	Key   string
+	Seed  int64
+	// HasSeed is false when the seed was not given, the caller picks one and prints it
+	HasSeed bool
+}
+
+// Parse returns the policy of its string form
+func Parse(policy string) (Policy, error) {
+	fields := strings.Split(strings.TrimSpace(policy), ":")
+	mode := Mode(strings.ToLower(fields[0]))
+	args := fields[1:]
+	switch mode {
+	case All:
+		if len(args) != 0 {
+			return Policy{}, fmt.Errorf("dimension sampling %q takes no argument", policy)
+		}
+		return Policy{Mode: All}, nil
+	case First:
+		if len(args) != 1 {
+			return Policy{}, fmt.Errorf("dimension sampling %q should be first:<count>", policy)
+		}
+		count, err := parseCount(args[0])
+		if err != nil {
+			return Policy{}, err
+		}
+		return Policy{Mode: First, Count: count}, nil
+	case Stratified:
+		if len(args) < 1 || len(args) > 2 || args[0] == "" {
+			return Policy{}, fmt.Errorf("dimension sampling %q should be stratified:<key>[:<count>]", policy)
+		}
+		p := Policy{Mode: Stratified, Key: args[0], Count: 1}
+		if len(args) == 2 {
+			count, err := parseCount(args[1])
+			if err != nil {
+				return Policy{}, err
+			}
+			p.Count = count
+		}
+		return p, nil
+	case Random:
+		if len(args) < 1 || len(args) > 2 {
+			return Policy{}, fmt.Errorf("dimension sampling %q should be random:<count>[:<seed>]", policy)
+		}
+		count, err := parseCount(args[0])
+		if err != nil {
+			return Policy{}, err
+		}
+		p := Policy{Mode: Random, Count: count}
+		if len(args) == 2 {
+			if p.Seed, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+				return Policy{}, fmt.Errorf("invalid dimension sampling seed %q", args[1])
+			}
+			p.HasSeed = true
+		}
+		return p, nil
+	default:
+		return Policy{}, fmt.Errorf("unknown dimension sampling %q, expected all, first, stratified or random", policy)
+	}
+}
+
+func parseCount(count string) (int, error) {
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid dimension sampling count %q", count)
+	}
+	return n, nil
+}
+
+// String returns the policy in the form Parse reads, with the seed so a random run can be repeated
+func (p Policy) String() string {
+	switch p.Mode {
+	case First:
+		return fmt.Sprintf("%s:%d", p.Mode, p.Count)
+	case Stratified:
+		return fmt.Sprintf("%s:%s:%d", p.Mode, p.Key, p.Count)
+	case Random:
+		return fmt.Sprintf("%s:%d:%d", p.Mode, p.Count, p.Seed)
+	default:
+		return string(p.Mode)
+	}
+}
@@ -6,17 +6,28 @@ package environment
 import (
 	"flag"
 	"log"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/agentcontrollertype"
 	"github.com/aws/amazon-cloudwatch-agent-test/environment/computetype"
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/dimensionsampling"
 	"github.com/aws/amazon-cloudwatch-agent-test/environment/ecsdeploymenttype"
 	"github.com/aws/amazon-cloudwatch-agent-test/environment/ecslaunchtype"
 	"github.com/aws/amazon-cloudwatch-agent-test/environment/eksdeploymenttype"
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/testselector"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
 )
 
 const (
 	DefaultEC2AgentStartCommand = "sudo /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl -a fetch-config -m ec2 -s -c "
+	// TestSelectorEnvVar is read when the testSelector flag is not set
+	TestSelectorEnvVar = "CWA_TEST_SELECTOR"
+	// defaultDimensionSampling validates one random dimension set per metric, as before the sampling policies
+	defaultDimensionSampling = "random:1"
 )
 
 var metaDataStorage *MetaData = nil
@@ -45,6 +56,17 @@ type MetaData struct {
 	AgentStartCommand         string
 	EksGpuType                string
 	AmpWorkspaceId            string
+	ReportDirectory           string
+	DiagnosticsDirectory      string
+	DiagnosticsBucket         string
+	AgentController           agentcontrollertype.AgentControllerType
+	AgentBinaryDirectory      string
+	AgentImage                string
+	TestSelector              *testselector.Selector
+	ListTests                 bool // only print the selected tests without running them
+	RunId                     string
+	MockServerEndpoint        string
+	DimensionSampling         dimensionsampling.Policy
 }
 
 type MetaDataStrings struct {
@@ -69,6 +91,18 @@ type MetaDataStrings struct {
 	AgentStartCommand         string
 	EksGpuType                string
 	AmpWorkspaceId            string
+	ReportDirectory           string
+	DiagnosticsDirectory      string
+	DiagnosticsBucket         string
+	AgentController           string
+	AgentBinaryDirectory      string
+	AgentImage                string
+	TestSelector              string
+	ListTests                 bool
+	RunId                     string
+	MockServerEndpoint        string
+	DimensionSampling         string
+	DimensionSamplingSeed     int64 // seed of the random dimension sampling without a seed
 }
 
 func registerComputeType(dataString *MetaDataStrings) {
@@ -222,6 +256,86 @@ func registerAmpWorkspaceId(dataString *MetaDataStrings) {
 	flag.StringVar(&(dataString.AmpWorkspaceId), "ampWorkspaceId", "", "workspace Id for Amazon Managed Prometheus (AMP)")
 }
 
+func registerReportDirectory(dataString *MetaDataStrings) {
+	flag.StringVar(&(dataString.ReportDirectory), "reportDirectory", "", "Directory to write the JUnit XML, JSON and Markdown test result reports. Default is empty, which only logs the results")
+}
+
+func registerDiagnostics(dataString *MetaDataStrings) {
+	flag.StringVar(&(dataString.DiagnosticsDirectory), "diagnosticsDirectory", "", "Directory to write a diagnostics bundle to when a test runner fails. Default is empty, which collects no diagnostics")
+	flag.StringVar(&(dataString.DiagnosticsBucket), "diagnosticsBucket", "", "s3 bucket to upload the diagnostics bundles to. Default is empty, which keeps them local")
+}
+
+func registerAgentController(dataString *MetaDataStrings) {
+	flag.StringVar(&(dataString.AgentController), "agentController", string(agentcontrollertype.CTL),
+		"CTL/BINARY/DOCKER. CTL uses the installed agent, BINARY runs the binaries in agentBinaryDirectory and DOCKER runs agentImage in a local container")
+	flag.StringVar(&(dataString.AgentBinaryDirectory), "agentBinaryDirectory", "", "Directory with the amazon-cloudwatch-agent and config-translator binaries for the BINARY agent controller")
+	flag.StringVar(&(dataString.AgentImage), "agentImage", "public.ecr.aws/cloudwatch-agent/cloudwatch-agent:latest", "Agent image for the DOCKER agent controller")
+}
+
+func fillAgentController(e *MetaData, data *MetaDataStrings) {
+	agentController, ok := agentcontrollertype.FromString(data.AgentController)
+	if !ok {
+		log.Printf("Invalid agent controller %s, using %s", data.AgentController, agentcontrollertype.CTL)
+		agentController = agentcontrollertype.CTL
+	}
+	e.AgentController = agentController
+	e.AgentBinaryDirectory = data.AgentBinaryDirectory
+	e.AgentImage = data.AgentImage
+}
+
+func registerTestSelection(dataString *MetaDataStrings) {
+	flag.StringVar(&(dataString.TestSelector), "testSelector", "",
+		"Boolean expression over test tags selecting the tests to run, e.g \"linux && fast && plugin:logs\". Default is empty, which is read from "+TestSelectorEnvVar)
+	flag.BoolVar(&(dataString.ListTests), "listTests", false, "Print the selected tests and their tags without running them")
+}
+
+func fillTestSelection(e *MetaData, data *MetaDataStrings) {
+	expression := data.TestSelector
+	if expression == "" {
+		expression = os.Getenv(TestSelectorEnvVar)
+	}
+	selector, err := testselector.Parse(expression)
+	if err != nil {
+		// Running every test on a typo would be worse than not running any
+		log.Fatal(err)
+	}
+	if expression != "" {
+		log.Printf("Selecting tests matching %s", selector)
+	}
+	e.TestSelector = selector
+	e.ListTests = data.ListTests
+}
+
+func registerAgentConfigVariables(dataString *MetaDataStrings) {
+	// The default is generated once here since the metadata is filled again on every GetEnvironmentMetaData
+	flag.StringVar(&(dataString.RunId), "runId", strings.Split(uuid.NewString(), "-")[0],
+		"ID added to the namespaces and log groups of the agent config templates so parallel runs in one account do not collide. Default is random")
+	flag.StringVar(&(dataString.MockServerEndpoint), "mockServerEndpoint", "https://127.0.0.1", "Endpoint of the mock server the agent config templates can send data to")
+}
+
+func registerDimensionSampling(dataString *MetaDataStrings) {
+	flag.StringVar(&(dataString.DimensionSampling), "dimensionSampling", defaultDimensionSampling,
+		"Dimension sets of each container insights metric to validate: all, first:<count>, stratified:<key>[:<count>] or random:<count>[:<seed>]. Default is one random set")
+	// The seed is generated once here since the metadata is filled again on every GetEnvironmentMetaData
+	dataString.DimensionSamplingSeed = time.Now().UnixNano()
+}
+
+func fillDimensionSampling(e *MetaData, data *MetaDataStrings) {
+	sampling := data.DimensionSampling
+	if sampling == "" {
+		sampling = defaultDimensionSampling
+	}
+	policy, err := dimensionsampling.Parse(sampling)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if policy.Mode == dimensionsampling.Random && !policy.HasSeed {
+		policy.Seed = data.DimensionSamplingSeed
+		policy.HasSeed = true
+	}
+	e.DimensionSampling = policy
+}
+
 func RegisterEnvironmentMetaDataFlags() *MetaDataStrings {
 	registerComputeType(registeredMetaDataStrings)
 	registerECSData(registeredMetaDataStrings)
@@ -238,6 +352,12 @@ func RegisterEnvironmentMetaDataFlags() *MetaDataStrings {
 	registerInstancePlatform(registeredMetaDataStrings)
 	registerAgentStartCommand(registeredMetaDataStrings)
 	registerAmpWorkspaceId(registeredMetaDataStrings)
+	registerReportDirectory(registeredMetaDataStrings)
+	registerDiagnostics(registeredMetaDataStrings)
+	registerAgentController(registeredMetaDataStrings)
+	registerTestSelection(registeredMetaDataStrings)
+	registerAgentConfigVariables(registeredMetaDataStrings)
+	registerDimensionSampling(registeredMetaDataStrings)
 
 	return registeredMetaDataStrings
 }
@@ -253,6 +373,9 @@ func GetEnvironmentMetaData() *MetaData {
 	fillEKSData(metaDataStorage, registeredMetaDataStrings)
 	fillEC2PluginTests(metaDataStorage, registeredMetaDataStrings)
 	fillExcludedTests(metaDataStorage, registeredMetaDataStrings)
+	fillAgentController(metaDataStorage, registeredMetaDataStrings)
+	fillTestSelection(metaDataStorage, registeredMetaDataStrings)
+	fillDimensionSampling(metaDataStorage, registeredMetaDataStrings)
 	metaDataStorage.Bucket = registeredMetaDataStrings.Bucket
 	metaDataStorage.S3Key = registeredMetaDataStrings.S3Key
 	metaDataStorage.CwaCommitSha = registeredMetaDataStrings.CwaCommitSha
@@ -264,6 +387,11 @@ func GetEnvironmentMetaData() *MetaData {
 	metaDataStorage.AgentStartCommand = registeredMetaDataStrings.AgentStartCommand
 	metaDataStorage.EksGpuType = registeredMetaDataStrings.EksGpuType
 	metaDataStorage.AmpWorkspaceId = registeredMetaDataStrings.AmpWorkspaceId
+	metaDataStorage.ReportDirectory = registeredMetaDataStrings.ReportDirectory
+	metaDataStorage.DiagnosticsDirectory = registeredMetaDataStrings.DiagnosticsDirectory
+	metaDataStorage.DiagnosticsBucket = registeredMetaDataStrings.DiagnosticsBucket
+	metaDataStorage.RunId = registeredMetaDataStrings.RunId
+	metaDataStorage.MockServerEndpoint = registeredMetaDataStrings.MockServerEndpoint
 
 	return metaDataStorage
 }
@@ -0,0 +1,228 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package testselector
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Selector is a boolean expression over test tags, e.g "linux && fast && (plugin:cpu || plugin:mem) && !needs-root".
+// The operators are !, && and || (or not, and and or) and parentheses group sub-expressions. A tag matches a test
+// declaring it, ignoring case.
+type Selector struct {
+	expression string
+	root       node
+}
+
+type node interface {
+	match(tags map[string]struct{}) bool
+}
+
+type tagNode string
+
+func (n tagNode) match(tags map[string]struct{}) bool {
+	_, ok := tags[string(n)]
+	return ok
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) match(tags map[string]struct{}) bool {
+	return !n.operand.match(tags)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(tags map[string]struct{}) bool {
+	return n.left.match(tags) && n.right.match(tags)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(tags map[string]struct{}) bool {
+	return n.left.match(tags) || n.right.match(tags)
+}
+
+// Parse returns the selector for the expression. An empty expression selects every test.
+func Parse(expression string) (*Selector, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	s := &Selector{expression: strings.TrimSpace(expression)}
+	if len(tokens) == 0 {
+		return s, nil
+	}
+	p := &parser{tokens: tokens}
+	if s.root, err = p.parseOr(); err != nil {
+		return nil, fmt.Errorf("invalid test selector %q: %w", expression, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid test selector %q: unexpected %q", expression, p.tokens[p.pos])
+	}
+	return s, nil
+}
+
+// Match returns whether a test with the tags is selected
+func (s *Selector) Match(tags []string) bool {
+	if s == nil || s.root == nil {
+		return true
+	}
+	set := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		set[strings.ToLower(tag)] = struct{}{}
+	}
+	return s.root.match(set)
+}
+
+func (s *Selector) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.expression
+}
+
+const (
+	tokenNot    = "!"
+	tokenAnd    = "&&"
+	tokenOr     = "||"
+	tokenLParen = "("
+	tokenRParen = ")"
+)
+
+var keywords = map[string]string{
+	"not": tokenNot,
+	"and": tokenAnd,
+	"or":  tokenOr,
+}
+
+func tokenize(expression string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '!' || r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("invalid test selector %q: expected %c%c at offset %d", expression, r, r, i)
+			}
+			tokens = append(tokens, string([]rune{r, r}))
+			i += 2
+		case isTagRune(r):
+			start := i
+			for i < len(runes) && isTagRune(runes[i]) {
+				i++
+			}
+			word := strings.ToLower(string(runes[start:i]))
+			if keyword, ok := keywords[word]; ok {
+				word = keyword
+			}
+			tokens = append(tokens, word)
+		default:
+			return nil, fmt.Errorf("invalid test selector %q: unexpected %q at offset %d", expression, r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-:./", r)
+}
+
+func isOperator(token string) bool {
+	switch token {
+	case tokenNot, tokenAnd, tokenOr, tokenLParen, tokenRParen:
+		return true
+	}
+	return false
+}
+
+// parser is a recursive descent parser where ! binds tighter than && which binds tighter than ||
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == tokenOr {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == tokenAnd {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == tokenNot:
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case token == tokenLParen:
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != tokenRParen {
+			return nil, fmt.Errorf("missing %q", tokenRParen)
+		}
+		p.pos++
+		return n, nil
+	case isOperator(token):
+		return nil, fmt.Errorf("unexpected %q", token)
+	default:
+		p.pos++
+		return tagNode(token), nil
+	}
+}
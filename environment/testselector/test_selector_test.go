@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package testselector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatch(t *testing.T) {
+	testCases := map[string]struct {
+		expression string
+		tags       []string
+		want       bool
+	}{
+		"empty selects every test":      {expression: "", tags: []string{"linux"}, want: true},
+		"blank selects every test":      {expression: "  ", tags: nil, want: true},
+		"tag":                           {expression: "linux", tags: []string{"linux", "fast"}, want: true},
+		"missing tag":                   {expression: "windows", tags: []string{"linux", "fast"}, want: false},
+		"tag ignores case":              {expression: "Linux", tags: []string{"LINUX"}, want: true},
+		"tag with separators":           {expression: "plugin:cpu", tags: []string{"plugin:cpu"}, want: true},
+		"unknown tag matches nothing":   {expression: "no-such-tag", tags: []string{"linux"}, want: false},
+		"unknown tag negated":           {expression: "!no-such-tag", tags: []string{"linux"}, want: true},
+		"and":                           {expression: "linux && fast", tags: []string{"linux", "fast"}, want: true},
+		"and with missing tag":          {expression: "linux && fast", tags: []string{"linux"}, want: false},
+		"or":                            {expression: "windows || linux", tags: []string{"linux"}, want: true},
+		"or without any tag":            {expression: "windows || darwin", tags: []string{"linux"}, want: false},
+		"not":                           {expression: "!needs-root", tags: []string{"linux"}, want: true},
+		"not with tag":                  {expression: "!needs-root", tags: []string{"needs-root"}, want: false},
+		"double not":                    {expression: "!!linux", tags: []string{"linux"}, want: true},
+		"keywords":                      {expression: "linux and not slow or windows", tags: []string{"linux"}, want: true},
+		"keywords ignore case":          {expression: "linux AND NOT slow", tags: []string{"linux", "slow"}, want: false},
+		"and binds tighter than or":     {expression: "windows && fast || linux", tags: []string{"linux"}, want: true},
+		"and binds tighter than or too": {expression: "linux || windows && fast", tags: []string{"linux"}, want: true},
+		"not binds tighter than and":    {expression: "!slow && linux", tags: []string{"linux"}, want: true},
+		"not binds tighter than or":     {expression: "!linux || fast", tags: []string{"linux"}, want: false},
+		"parentheses group or":          {expression: "(windows || linux) && fast", tags: []string{"linux"}, want: false},
+		"parentheses group or matching": {expression: "(windows || linux) && fast", tags: []string{"linux", "fast"}, want: true},
+		"not of group":                  {expression: "!(linux && fast)", tags: []string{"linux"}, want: true},
+		"nested parentheses":            {expression: "((linux)) && !(slow || (needs-root))", tags: []string{"linux", "needs-root"}, want: false},
+		"full example": {
+			expression: "linux && fast && (plugin:cpu || plugin:mem) && !needs-root",
+			tags:       []string{"linux", "fast", "plugin:mem"},
+			want:       true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			selector, err := Parse(testCase.expression)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, selector.Match(testCase.tags))
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	testCases := map[string]string{
+		"single ampersand":        "linux & fast",
+		"single pipe":             "linux | fast",
+		"trailing and":            "linux &&",
+		"leading or":              "|| linux",
+		"double operator":         "linux && || fast",
+		"trailing not":            "linux && !",
+		"missing right paren":     "(linux && fast",
+		"unexpected right paren":  "linux)",
+		"empty parentheses":       "()",
+		"missing operator":        "linux fast",
+		"missing operator before": "linux (fast)",
+		"invalid character":       "linux && fa$t",
+		"keyword only":            "and",
+	}
+	for name, expression := range testCases {
+		t.Run(name, func(t *testing.T) {
+			selector, err := Parse(expression)
+			assert.Error(t, err)
+			assert.Nil(t, selector)
+		})
+	}
+}
+
+func TestNilSelector(t *testing.T) {
+	var selector *Selector
+	assert.True(t, selector.Match([]string{"linux"}))
+	assert.Equal(t, "", selector.String())
+}
+
+func TestSelectorString(t *testing.T) {
+	selector, err := Parse("  linux && fast ")
+	require.NoError(t, err)
+	assert.Equal(t, "linux && fast", selector.String())
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/exp/slices"
@@ -145,7 +146,15 @@ var testTypeToTestConfig = map[string][]testConfig{
 		{testDir: "../../test/performance/system"},
 		{testDir: "../../test/performance/statsd"},
 		{testDir: "../../test/performance/collectd"},
+		{testDir: "../../test/performance/otlp"},
 		{testDir: "../../test/performance/trace/xray", runMockServer: true},
+		// Fluent Bit is installed and started by the setup.sh of the test directories
+		{testDir: "../../test/performance/fluent"},
+		// the feature validator sends a fixed load, so it only runs once
+		{
+			testDir: "../../test/feature/fluent",
+			targets: map[string]map[string]struct{}{"valuesPerMinute": {"100": {}}},
+		},
 	},
 	"ec2_windows_performance": {
 		{testDir: "../../test/performance/windows/logs"},
@@ -340,6 +349,8 @@ func shouldAddTest(row *matrixRow, targets map[string]map[string]struct{}) bool
 			rowVal = row.Os
 		} else if key == "metadataEnabled" {
 			rowVal = row.MetadataEnabled
+		} else if key == "valuesPerMinute" {
+			rowVal = strconv.Itoa(row.ValuesPerMinute)
 		}
 
 		if rowVal == "" {
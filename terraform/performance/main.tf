@@ -19,6 +19,8 @@ locals {
   temp_directory    = local.ami_family["temp_folder"]
   connection_type   = local.ami_family["connection_type"]
   start_command     = format(local.ami_family["start_command"], module.validator.instance_agent_config)
+  // setup.sh in the test directory of the cloned test repo prepares the host (e.g starts Fluent Bit) when it exists
+  test_setup_script = "amazon-cloudwatch-agent-test/test/${element(split("/test/", var.test_dir), 1)}/setup.sh"
 }
 
 
@@ -115,6 +117,8 @@ resource "null_resource" "validator_linux" {
       var.run_mock_server ? "sudo docker run --name mockserver -d -p 8080:8080 -p 443:443  mockserver" : "echo skipping mock server run",
       "cp -r amazon-cloudwatch-agent-test/test/xray/resources /home/ec2-user/",
       "export AWS_REGION=${var.region}",
+      "export INSTANCE_ID=${aws_instance.cwagent.id}",
+      "if [ -f ${local.test_setup_script} ]; then bash ${local.test_setup_script}; fi",
       "cd ./validator/validators",
       "sudo chmod +x ./${local.install_validator}",
       "./${local.install_validator} --validator-config=${module.validator.instance_validator_config} --preparation-mode=true",
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_config_lint
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/agentconfig"
+)
+
+const (
+	repoRoot = "../.."
+	// agentSchemaEnvVar is the path of the agent config schema to validate with instead of the embedded subset
+	// (translator/config/schema.json in the amazon-cloudwatch-agent repo)
+	agentSchemaEnvVar = "CWA_AGENT_CONFIG_SCHEMA"
+)
+
+// TestAgentConfigs checks every agent config of the repo without the network, so a broken agent config fails
+// before the agent refuses to start on a test host
+func TestAgentConfigs(t *testing.T) {
+	checker, err := newChecker()
+	if err != nil {
+		t.Fatalf("failed to create the agent config checker: %v", err)
+	}
+	files, err := agentconfig.FindConfigFiles(repoRoot)
+	if err != nil {
+		t.Fatalf("failed to find the agent configs: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("found no agent configs in %s", repoRoot)
+	}
+
+	// The templates are rendered with fixed variables, the same run id keeps the log groups of the tests comparable
+	variables := common.NewAgentConfigVariables(&environment.MetaData{
+		RunId:              "lint",
+		InstanceId:         "i-00000000000000000",
+		CaCertPath:         "/etc/ssl/certs/ca-bundle.crt",
+		ProxyUrl:           "http://127.0.0.1:3128",
+		AmpWorkspaceId:     "ws-00000000-0000-0000-0000-000000000000",
+		MockServerEndpoint: "https://127.0.0.1",
+	})
+	var rendered []agentconfig.ConfigFile
+	for _, file := range files {
+		data, err := common.RenderAgentConfig(file.Path, variables)
+		if err != nil {
+			t.Errorf("%s: %v", file.Path, err)
+			continue
+		}
+		file.Data = data
+		rendered = append(rendered, file)
+	}
+
+	for _, finding := range checker.Check(rendered) {
+		t.Error(finding)
+	}
+}
+
+func newChecker() (*agentconfig.Checker, error) {
+	schemaPath := os.Getenv(agentSchemaEnvVar)
+	if schemaPath == "" {
+		return agentconfig.NewChecker()
+	}
+	schema, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return agentconfig.NewCheckerWithSchema(schema)
+}
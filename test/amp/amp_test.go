@@ -13,7 +13,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -28,7 +27,6 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
-	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 type AMPResponse struct {
@@ -99,29 +97,36 @@ func (t *AmpDestinationTestRunner) validateMetric(metricName string) status.Test
 		Name:   metricName,
 		Status: status.FAILED,
 	}
+	testResult.AddIdentifier("amp_workspace_id", env.AmpWorkspaceId)
+	testResult.AddIdentifier("metric_name", metricName)
 
 	// NOTE: dims must match aggregation_dimensions from agent config to fetch metrics.
 	// the idea is to fetch all metrics including non-aggregated metrics with matching dim set
 	// then validate if the returned list of metrics include metrics (non-aggregated) with append_dimensions as labels
 	dims := getDimensions()
 	if len(dims) == 0 {
+		testResult.Fail("no dimensions")
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	res, err := queryAMPMetrics(env.AmpWorkspaceId, buildPrometheusQuery(metricName, dims))
 	if err != nil {
 		fmt.Printf("failed to fetch metric values from AMP for %s: %s\n", metricName, err)
+		testResult.Fail("failed to fetch metric values from AMP: %v", err)
 		return testResult
 	}
 	var responseJson AMPResponse
 	err = json.Unmarshal(res, &responseJson)
 	if err != nil {
 		fmt.Printf("failed to unmarshal AMP response: %s\n", err)
+		testResult.Fail("failed to unmarshal AMP response: %v", err)
 		return testResult
 	}
 
 	if len(responseJson.Data.Result) == 0 {
 		fmt.Printf("AMP metric values are missing for %s\n", metricName)
+		testResult.Fail("AMP metric values are missing")
 		return testResult
 	}
 
@@ -145,10 +150,13 @@ func (t *AmpDestinationTestRunner) validateMetric(metricName string) status.Test
 	// 1 non-aggregated + 1 aggregated minimum
 	if len(metricVals) < 2 || !foundAppendDimMetric {
 		fmt.Println("failed with less metric values than expected or missing append_dimensions")
+		testResult.Fail("less metric values than expected or missing append_dimensions")
+		testResult.Expected = "at least 2 values with append_dimensions"
+		testResult.Observed = fmt.Sprintf("%d values, append_dimensions found %t", len(metricVals), foundAppendDimMetric)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, metricVals, 0) {
+	if !metric.ValidateValues(&testResult, metricName, metricVals, 0) {
 		return testResult
 	}
 
@@ -173,31 +181,20 @@ func (t AmpDestinationTestRunner) GetMeasuredMetrics() []string {
 	}
 }
 
+// SetupBeforeAgentRun renders the agent config template with the testing AMP workspace ID from the ampWorkspaceId flag
 func (t *AmpDestinationTestRunner) SetupBeforeAgentRun() error {
-	env := environment.GetEnvironmentMetaData()
-	err := t.BaseTestRunner.SetupBeforeAgentRun()
-	if err != nil {
-		return err
-	}
-	// replace AMP workspace ID placeholder with a testing workspace ID from metadata
-	agentConfigPath := filepath.Join("agent_configs", t.GetAgentConfigFileName())
-	ampCommands := []string{
-		"sed -ie 's/{workspace_id}/" + env.AmpWorkspaceId + "/g' " + agentConfigPath,
-		// use below to add JMX metrics then update agent config & GetMeasuredMetrics()
-		//"nohup java -Dcom.sun.management.jmxremote -Dcom.sun.management.jmxremote.port=2030 -Dcom.sun.management.jmxremote.local.only=false -Dcom.sun.management.jmxremote.authenticate=false -Dcom.sun.management.jmxremote.ssl=false -Dcom.sun.management.jmxremote.rmi.port=2030  -Dcom.sun.management.jmxremote.host=0.0.0.0  -Djava.rmi.server.hostname=0.0.0.0 -Dserver.port=8090 -Dspring.application.admin.enabled=true -jar jars/spring-boot-web-starter-tomcat.jar > /tmp/spring-boot-web-starter-tomcat-jar.txt 2>&1 &",
-	}
-	err = common.RunCommands(ampCommands)
-	if err != nil {
-		return err
-	}
-	return t.SetUpConfig()
+	// use below to add JMX metrics then update agent config & GetMeasuredMetrics()
+	//common.RunCommand("nohup java -Dcom.sun.management.jmxremote -Dcom.sun.management.jmxremote.port=2030 -Dcom.sun.management.jmxremote.local.only=false -Dcom.sun.management.jmxremote.authenticate=false -Dcom.sun.management.jmxremote.ssl=false -Dcom.sun.management.jmxremote.rmi.port=2030  -Dcom.sun.management.jmxremote.host=0.0.0.0  -Djava.rmi.server.hostname=0.0.0.0 -Dserver.port=8090 -Dspring.application.admin.enabled=true -jar jars/spring-boot-web-starter-tomcat.jar > /tmp/spring-boot-web-starter-tomcat-jar.txt 2>&1 &")
+	return t.BaseTestRunner.SetupBeforeAgentRun()
 }
 
 func TestAmp(t *testing.T) {
 	runner := test_runner.TestRunner{TestRunner: &AmpDestinationTestRunner{
 		test_runner.BaseTestRunner{},
 	}}
-	result := runner.Run()
+	ctx, cancel := test_runner.Context(t)
+	defer cancel()
+	result := runner.Run(ctx)
 	if result.GetStatus() != status.SUCCESSFUL {
 		t.Fatal("AMP Destination test failed")
 		result.Print()
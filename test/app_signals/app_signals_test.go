@@ -36,6 +36,7 @@ func (suite *AppSignalsTestSuite) SetupSuite() {
 
 func (suite *AppSignalsTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("AppSignalsTestSuite")
 	fmt.Println(">>>> Finished AppSignalsTestSuite")
 }
 
@@ -99,8 +100,10 @@ func (suite *AppSignalsTestSuite) TestAllInSuite() {
 		}
 	case computetype.EC2:
 		log.Println("Environment compute type is EC2")
-		for _, testRunner := range getEc2TestRunners(env) {
-			suite.AddToSuiteResult(testRunner.Run())
+		ctx, cancel := test_runner.Context(suite.T())
+		defer cancel()
+		for _, testRunner := range test_runner.SelectTestRunnersByTags(env, getEc2TestRunners(env)) {
+			suite.AddToSuiteResult(testRunner.Run(ctx))
 		}
 	default:
 		return
@@ -6,6 +6,7 @@
 package app_signals
 
 import (
+	"context"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -67,7 +68,7 @@ func (e *AppSignalsMetricsRunner) GetAgentConfigFileName() string {
 	return "config.json"
 }
 
-func (e *AppSignalsMetricsRunner) SetupAfterAgentRun() error {
+func (e *AppSignalsMetricsRunner) SetupAfterAgentRun(context.Context) error {
 	// sends metrics data only for EC2
 	if e.computeType == computetype.EC2 {
 		common.RunCommand("pwd")
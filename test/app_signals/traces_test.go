@@ -6,6 +6,7 @@
 package app_signals
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -85,7 +86,7 @@ func (e *AppSignalsTracesRunner) GetAgentConfigFileName() string {
 	return "config.json"
 }
 
-func (e *AppSignalsTracesRunner) SetupAfterAgentRun() error {
+func (e *AppSignalsTracesRunner) SetupAfterAgentRun(context.Context) error {
 	// sends metrics data only for EC2
 	if e.computeType == computetype.EC2 {
 		cmd := `while true; chmod +x ./resources/traceid_generator.go; export START_TIME=$(date +%s%N); export TRACE_ID=$(go run ./resources/traceid_generator.go); do 
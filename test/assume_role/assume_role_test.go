@@ -14,7 +14,9 @@ import (
 
 func TestAssumeRole(t *testing.T) {
 	runner := test_runner.TestRunner{TestRunner: &RoleTestRunner{test_runner.BaseTestRunner{}}}
-	result := runner.Run()
+	ctx, cancel := test_runner.Context(t)
+	defer cancel()
+	result := runner.Run(ctx)
 	if result.GetStatus() != status.SUCCESSFUL {
 		t.Fatal("Assume Role Test failed")
 		result.Print()
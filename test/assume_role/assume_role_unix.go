@@ -46,25 +46,25 @@ func (t RoleTestRunner) Validate() status.TestGroupResult {
 }
 
 func (t *RoleTestRunner) validateMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace(namespace), metricName)
 
 	dims := getDimensions(environment.GetEnvironmentMetaData().InstanceId)
 	if len(dims) == 0 {
+		testResult.Fail("no dimensions")
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace(namespace), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
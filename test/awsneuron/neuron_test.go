@@ -29,6 +29,7 @@ func (suite *AwsNeuronTestSuite) SetupSuite() {
 
 func (suite *AwsNeuronTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("AwsNeuronTestSuite")
 	fmt.Println(">>>> Finished AWS Neuron Container Insights TestSuite")
 }
 
@@ -81,9 +81,10 @@ func TestBundle(t *testing.T) {
 		log.Printf("common config file location %s agent config file %s find target %t", commonConfigFile, configFile, parameter.findTarget)
 		t.Run(fmt.Sprintf("common config file location %s agent config file %s find target %t", commonConfigFile, configFile, parameter.findTarget), func(t *testing.T) {
 			common.RecreateAgentLogfile(logfile)
-			common.ReplaceLocalStackHostName(configFile)
-			t.Logf("config file after localstack host replace %s", string(readFile(configFile)))
-			common.CopyFile(configFile, configOutputPath)
+			if err := common.CopyAgentConfig(configFile, configOutputPath, common.NewAgentConfigVariables(metadata)); err != nil {
+				t.Fatalf("Failed to render agent config %s: %v", configFile, err)
+			}
+			t.Logf("config file after localstack host replace %s", string(readFile(configOutputPath)))
 			common.CopyFile(commonConfigFile, commonConfigOutputPath)
 			common.StartAgent(configOutputPath, true, false)
 			// this command will take 5 seconds time 12 = 1 minute
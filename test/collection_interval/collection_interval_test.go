@@ -6,7 +6,9 @@
 package collection_interval
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"os"
 	"testing"
 	"time"
@@ -15,6 +17,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
@@ -30,8 +34,11 @@ const (
 type input struct {
 	lowerBoundInclusive int
 	upperBoundInclusive int
-	dataInput           string
-	testDescription     string
+	// collectionInterval of the disk plugin, the metrics collected more often than once a minute are checked to be
+	// stored at high resolution
+	collectionInterval time.Duration
+	dataInput          string
+	testDescription    string
 }
 
 func init() {
@@ -47,24 +54,28 @@ func TestCollectionInterval(t *testing.T) {
 	parameters := []input{
 		{
 			testDescription:     "No collection interval given default to 60s",
+			collectionInterval:  time.Minute,
 			dataInput:           "resources/default.json",
 			lowerBoundInclusive: 1,
 			upperBoundInclusive: 3,
 		},
 		{
 			testDescription:     "Agent has 10s second collection interval",
+			collectionInterval:  10 * time.Second,
 			dataInput:           "resources/agent_interval_10s.json",
 			lowerBoundInclusive: 11,
 			upperBoundInclusive: 13,
 		},
 		{
 			testDescription:     "Metric disk has 10s collection interval",
+			collectionInterval:  10 * time.Second,
 			dataInput:           "resources/metric_interval_10s.json",
 			lowerBoundInclusive: 11,
 			upperBoundInclusive: 13,
 		},
 		{
 			testDescription:     "Agent has 60s collection interval, disk has 10s collection interval use disk collection interval",
+			collectionInterval:  10 * time.Second,
 			dataInput:           "resources/metric_override_interval_10s.json",
 			lowerBoundInclusive: 11,
 			upperBoundInclusive: 13,
@@ -98,7 +109,8 @@ func TestCollectionInterval(t *testing.T) {
 				endTime := time.Now()
 				if awsservice.ValidateSampleCount(metricName, common.Namespace, dimensions,
 					startTime, endTime,
-					parameter.lowerBoundInclusive, parameter.upperBoundInclusive, periodInSeconds) {
+					parameter.lowerBoundInclusive, parameter.upperBoundInclusive, periodInSeconds) &&
+					validateHighResolution(dimensions, startTime, endTime, parameter.collectionInterval) {
 					pass = true
 					break
 				}
@@ -109,3 +121,21 @@ func TestCollectionInterval(t *testing.T) {
 		})
 	}
 }
+
+// validateHighResolution checks the metric collected more often than once a minute is stored at high resolution in
+// the time range of the agent run (see metric.HighResolutionChecks)
+func validateHighResolution(dimensions []types.Dimension, startTime, endTime time.Time, collectionInterval time.Duration) bool {
+	if collectionInterval >= time.Minute {
+		return true
+	}
+	validator := metric.BatchValidator{StartTime: startTime, EndTime: endTime}
+	results := validator.Validate(context.Background(),
+		metric.HighResolutionChecks(common.Namespace, metricName, dimensions, collectionInterval))
+	for _, result := range results {
+		if result.Status != status.SUCCESSFUL {
+			log.Printf("%s failed: %s", result.Name, result.Reason)
+			return false
+		}
+	}
+	return true
+}
@@ -74,22 +74,22 @@ func (t *EMFTestRunner) validateEMFMetrics(metricName string) status.TestResult
 			Value: dimension.ExpectedDimensionValue{Value: aws.String("Counter")},
 		},
 	})
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace, metricName)
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
 	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
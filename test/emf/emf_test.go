@@ -30,6 +30,7 @@ func (suite *MetricBenchmarkTestSuite) SetupSuite() {
 
 func (suite *MetricBenchmarkTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("EMFContainerTestSuite")
 	fmt.Println(">>>> Finished EMF Container TestSuite")
 }
 
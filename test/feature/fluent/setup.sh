@@ -0,0 +1,9 @@
+#!/bin/bash
+# Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+# SPDX-License-Identifier: MIT
+
+# Installs and starts Fluent Bit with the fluent-bit.conf of the feature test
+set -euo pipefail
+
+dir=$(dirname "$0")
+bash "$dir/../../performance/fluent/setup.sh" "$dir/fluent-bit.conf"
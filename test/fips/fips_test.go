@@ -50,10 +50,7 @@ func TestFIPS(t *testing.T) {
 }
 
 func validateCpuMetric(metricName string, factory dimension.Factory) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace, metricName)
 
 	dims, failed := factory.GetDimensions([]dimension.Instruction{
 		{
@@ -67,18 +64,21 @@ func validateCpuMetric(metricName string, factory dimension.Factory) status.Test
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
 	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	log.Printf("metric values are %v", values)
 	if err != nil {
 		log.Printf("err: %v\n", err)
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
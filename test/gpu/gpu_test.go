@@ -30,6 +30,7 @@ func (suite *GPUTestSuite) SetupSuite() {
 
 func (suite *GPUTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("GPUTestSuite")
 	fmt.Println(">>>> Finished GPU Container Insights TestSuite")
 }
 
@@ -43,10 +43,7 @@ func (t LVMTestRunner) Validate() status.TestGroupResult {
 }
 
 func (t *LVMTestRunner) validateDiskMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace(namespace), metricName)
 
 	hostName, err := os.Hostname()
 	if err != nil {
@@ -62,18 +59,21 @@ func (t *LVMTestRunner) validateDiskMetric(metricName string) status.TestResult
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace(namespace), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -104,6 +104,16 @@ func (t *LVMTestRunner) SetupBeforeAgentRun() error {
 		"sudo mkdir /mnt/lvm",
 		"sudo mount /dev/mapper/vg0-lv0 /mnt/lvm/",
 	}
+	// registered first so a partial setup is undone as well, each command skips what was not created
+	t.Resources.RegisterCommands("lvm volume", []string{
+		"! mountpoint -q /mnt/lvm || sudo umount /mnt/lvm/",
+		"[ ! -d /mnt/lvm ] || sudo rmdir /mnt/lvm",
+		"! sudo lvs vg0/lv0 &> /dev/null || sudo lvremove -y vg0/lv0",
+		"! sudo vgs vg0 &> /dev/null || sudo vgremove -y vg0",
+		"! sudo pvs /dev/loop0 &> /dev/null || sudo pvremove -y /dev/loop0",
+		"! sudo losetup /dev/loop0 &> /dev/null || sudo losetup -d /dev/loop0",
+		"sudo rm -f /tmp/lvm0.img",
+	})
 	err := common.RunCommands(commands)
 	if err != nil {
 		return err
@@ -117,7 +127,9 @@ func TestLVM(t *testing.T) {
 	env := environment.GetEnvironmentMetaData()
 	factory := dimension.GetDimensionFactory(*env)
 	runner := test_runner.TestRunner{TestRunner: &LVMTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}}
-	result := runner.Run()
+	ctx, cancel := test_runner.Context(t)
+	defer cancel()
+	result := runner.Run(ctx)
 	if result.GetStatus() != status.SUCCESSFUL {
 		t.Fatal("LVM test failed")
 		result.Print()
@@ -55,23 +55,23 @@ var (
 )
 
 func ValidateAppSignalsMetric(dimFactory dimension.Factory, namespace string, metricName string, instructions []dimension.Instruction) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := NewTestResult(namespace, metricName)
 
 	dims, failed := dimFactory.GetDimensions(instructions)
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	AddDimensions(&testResult, dims)
 
 	fetcher := MetricValueFetcher{}
 	values, err := fetcher.Fetch(namespace, metricName, dims, SUM, HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -0,0 +1,215 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"go.uber.org/multierr"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+)
+
+const defaultBatchConcurrency = 4
+
+// MetricExpectation checks the values fetched for a MetricCheck
+type MetricExpectation func(metricName string, values MetricValues) error
+
+// ExpectAtLeast expects values as IsAllValuesGreaterThanOrEqualToExpectedValue does
+func ExpectAtLeast(expectedValue float64) MetricExpectation {
+	return func(metricName string, values MetricValues) error {
+		if !IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, expectedValue) {
+			return fmt.Errorf("values %v do not match the expected value %v", values, expectedValue)
+		}
+		return nil
+	}
+}
+
+// MetricCheck is a metric validated by the BatchValidator
+type MetricCheck struct {
+	// Name is the name of the test result, the metric name when empty
+	Name       string
+	Namespace  string
+	MetricName string
+	Dimensions []types.Dimension
+	Stat       Statistics
+	Period     int32
+	// Expectation is ExpectAtLeast(0) when nil
+	Expectation MetricExpectation
+	// Assertions check the shape of the series after the Expectation
+	Assertions []SeriesAssertion
+}
+
+// BatchValidator validates many metrics with GetMetricData requests of up to MaxQueriesPerRequest queries instead of a
+// request per metric
+type BatchValidator struct {
+	Fetcher MetricValueFetcher
+	// StartTime and EndTime are the last 10 minutes when not set, like Fetch
+	StartTime time.Time
+	EndTime   time.Time
+	// Concurrency bounds the GetMetricData requests in flight, 4 when not set
+	Concurrency int
+	// AllowPartialData validates the values of the metrics with partial data instead of failing them
+	AllowPartialData bool
+}
+
+// Validate returns a test result per check, in the order of the checks
+func (v BatchValidator) Validate(ctx context.Context, checks []MetricCheck) []status.TestResult {
+	results := make([]status.TestResult, len(checks))
+	for i, check := range checks {
+		results[i] = check.newTestResult()
+	}
+	startTime, endTime := v.timeRange()
+	v.forEachBatch(len(checks), MaxQueriesPerRequest, func(start, end int) {
+		v.validateBatch(ctx, startTime, endTime, checks[start:end], results[start:end])
+	})
+	return results
+}
+
+func (v BatchValidator) timeRange() (time.Time, time.Time) {
+	endTime := v.EndTime
+	if endTime.IsZero() {
+		endTime = time.Now()
+	}
+	startTime := v.StartTime
+	if startTime.IsZero() {
+		startTime = endTime.Add(-fetchWindow)
+	}
+	return startTime, endTime
+}
+
+// forEachBatch calls validate for each range of up to batchSize of the count items, with up to Concurrency calls in
+// flight, and returns once every call returned
+func (v BatchValidator) forEachBatch(count, batchSize int, validate func(start, end int)) {
+	concurrency := v.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for start := 0; start < count; start += batchSize {
+		end := start + batchSize
+		if end > count {
+			end = count
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			// each call writes its own range of the results
+			validate(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+func (v BatchValidator) validateBatch(ctx context.Context, startTime, endTime time.Time, checks []MetricCheck, results []status.TestResult) {
+	queries := make([]MetricQuery, len(checks))
+	for i, check := range checks {
+		queries[i] = MetricQuery{
+			Id:         fmt.Sprint("m", i),
+			Namespace:  check.Namespace,
+			MetricName: check.MetricName,
+			Dimensions: check.Dimensions,
+			Stat:       check.Stat,
+			Period:     check.Period,
+		}
+	}
+	log.Printf("Validating %d metrics with one GetMetricData request", len(queries))
+	dataResults, err := v.Fetcher.Query(ctx, MetricDataRequest{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Queries:   queries,
+		// partial data is failed per metric below instead of failing the whole batch
+		AllowPartialData: true,
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Fail("failed to fetch metric values: %v", err)
+		}
+		return
+	}
+
+	for i, dataResult := range dataResults {
+		check := checks[i]
+		values := dataResult.Values()
+		results[i].Observed = fmt.Sprint(values)
+		if dataResult.StatusCode == types.StatusCodePartialData && !v.AllowPartialData {
+			results[i].Fail("metric values are partial: %v", dataResult.Messages)
+			continue
+		}
+		expectation := check.Expectation
+		if expectation == nil {
+			expectation = ExpectAtLeast(0)
+		}
+		if err = expectation(check.MetricName, values); err != nil {
+			results[i].Fail("%v", err)
+			continue
+		}
+		series := dataResult.Series()
+		for _, assertion := range check.Assertions {
+			err = multierr.Append(err, assertion(series))
+		}
+		if err != nil {
+			results[i].Fail("%v", err)
+			continue
+		}
+		results[i].Status = status.SUCCESSFUL
+	}
+}
+
+// ValidateSeries validates the metrics sharing the dimensions and statistic in one batch, asserting the series of each
+// metric with the assertions the caller states for it
+func ValidateSeries(namespace string, metricNames []string, dimensions []types.Dimension, stat Statistics, period int32,
+	assertions func(metricName string) []SeriesAssertion) []status.TestResult {
+	checks := make([]MetricCheck, len(metricNames))
+	for i, metricName := range metricNames {
+		checks[i] = MetricCheck{
+			Namespace:   namespace,
+			MetricName:  metricName,
+			Dimensions:  dimensions,
+			Stat:        stat,
+			Period:      period,
+			Expectation: ExpectAtLeast(0),
+			Assertions:  assertions(metricName),
+		}
+	}
+	return BatchValidator{}.Validate(context.Background(), checks)
+}
+
+func (c MetricCheck) newTestResult() status.TestResult {
+	name := c.Name
+	if name == "" {
+		name = c.MetricName
+	}
+	result := status.TestResult{
+		Name:   name,
+		Status: status.FAILED,
+	}
+	result.AddIdentifier("namespace", c.Namespace)
+	result.AddIdentifier("metric_name", c.MetricName)
+	result.AddIdentifier("stat", string(c.Stat))
+	result.AddIdentifier("dimensions", formatDimensions(c.Dimensions))
+	return result
+}
+
+func formatDimensions(dimensions []types.Dimension) string {
+	pairs := make([]string, 0, len(dimensions))
+	for _, d := range dimensions {
+		pairs = append(pairs, aws.ToString(d.Name)+"="+aws.ToString(d.Value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
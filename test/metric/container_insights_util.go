@@ -6,11 +6,11 @@
 package metric
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"sort"
 	"strings"
 	"time"
@@ -38,8 +38,15 @@ type dimToMetrics struct {
 
 func ValidateMetrics(env *environment.MetaData, metricFilter string, expectedDimsToMetrics map[string][]string) []status.TestResult {
 	var results []status.TestResult
+	var checks []MetricCheck
+	sampler := NewDimensionSampler(env.DimensionSampling)
+	coverage := NewDimensionCoverage()
+	log.Printf("Sampling the dimension sets to validate with %s, rerun with -dimensionSampling=%s to pick the same sets",
+		env.DimensionSampling, env.DimensionSampling)
 	dimsToMetrics := getMetricsInClusterDimension(env, metricFilter)
-	for dims, metrics := range expectedDimsToMetrics {
+	// sorted so the random sampling picks the same sets for the same seed
+	for _, dims := range sortedKeys(expectedDimsToMetrics) {
+		metrics := expectedDimsToMetrics[dims]
 		var actual map[string][][]types.Dimension
 		for _, dtm := range dimsToMetrics {
 			if dtm.dimStr == dims {
@@ -48,31 +55,46 @@ func ValidateMetrics(env *environment.MetaData, metricFilter string, expectedDim
 			}
 		}
 		if len(actual) < 1 {
-			results = append(results, status.TestResult{
-				Name:   dims,
-				Status: status.FAILED,
-			})
+			result := status.TestResult{Name: dims}
+			result.AddIdentifier("namespace", ContainerInsightsNamespace)
+			result.AddIdentifier("dimensions", dims)
+			result.Fail("dimension set is missing")
+			results = append(results, result)
 			log.Printf("ValidateMetrics failed with missing dimension set: %s", dims)
 			// keep testing other dims or fail early?
 			continue
 		}
 		results = append(results, validateMetricsAvailability(dims, metrics, actual))
 		for _, m := range metrics {
-			// this is to prevent panic with rand.Intn when metrics are not yet ready in a cluster
 			if _, ok := actual[m]; !ok {
-				results = append(results, status.TestResult{
-					Name:   dims,
-					Status: status.FAILED,
-				})
+				result := status.TestResult{Name: dims}
+				result.AddIdentifier("namespace", ContainerInsightsNamespace)
+				result.AddIdentifier("metric_name", m)
+				result.AddIdentifier("dimensions", dims)
+				result.Fail("metric is missing")
+				results = append(results, result)
 				log.Printf("ValidateMetrics failed with missing metric: %s", m)
 				continue
 			}
-			// pick a random dimension set to test metric data OR test all dimension sets which might be overkill
-			randIdx := rand.Intn(len(actual[m]))
-			results = append(results, validateMetricValue(m, actual[m][randIdx]))
+			sampled := sampler.Sample(actual[m])
+			coverage.Add(actual[m], sampled)
+			for _, dimensionSet := range sampled {
+				checks = append(checks, metricValueCheck(m, dimensionSet))
+			}
 		}
 	}
-	return results
+	log.Printf("Validating %d dimension sets, series coverage: %s", len(checks), coverage)
+	// the metric values are fetched with a GetMetricData request per 500 metrics instead of one per metric
+	return append(results, BatchValidator{}.Validate(context.Background(), checks)...)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 func getMetricsInClusterDimension(env *environment.MetaData, metricFilter string) []dimToMetrics { //map[string]map[string]interface{} {
@@ -132,10 +154,20 @@ func validateMetricsAvailability(dims string, expected []string, actual map[stri
 		Name:   dims,
 		Status: status.FAILED,
 	}
+	testResult.AddIdentifier("namespace", ContainerInsightsNamespace)
+	testResult.AddIdentifier("dimensions", dims)
 	if compareMetrics(expected, actual) {
 		testResult.Status = status.SUCCESSFUL
 	} else {
 		log.Printf("validateMetricsAvailability failed for %s", dims)
+		actualMetrics := make([]string, 0, len(actual))
+		for name := range actual {
+			actualMetrics = append(actualMetrics, name)
+		}
+		sort.Strings(actualMetrics)
+		testResult.Fail("metrics do not match")
+		testResult.Expected = fmt.Sprint(expected)
+		testResult.Observed = fmt.Sprint(actualMetrics)
 	}
 	return testResult
 }
@@ -153,25 +185,17 @@ func compareMetrics(expected []string, actual map[string][][]types.Dimension) bo
 	return true
 }
 
-func validateMetricValue(name string, dims []types.Dimension) status.TestResult {
-	log.Printf("validateMetricValue with metric: %s", name)
-	testResult := status.TestResult{
-		Name:   name,
-		Status: status.FAILED,
+func metricValueCheck(name string, dims []types.Dimension) MetricCheck {
+	return MetricCheck{
+		// each validated dimension set is a test result
+		Name:        fmt.Sprintf("%s[%s]", name, formatDimensions(dims)),
+		Namespace:   ContainerInsightsNamespace,
+		MetricName:  name,
+		Dimensions:  dims,
+		Stat:        SAMPLE_COUNT,
+		Period:      MinuteStatPeriod,
+		Expectation: ExpectAtLeast(0),
 	}
-	valueFetcher := MetricValueFetcher{}
-	values, err := valueFetcher.Fetch(ContainerInsightsNamespace, name, dims, SAMPLE_COUNT, MinuteStatPeriod)
-	if err != nil {
-		log.Println("failed to fetch metrics", err)
-		return testResult
-	}
-
-	if !IsAllValuesGreaterThanOrEqualToExpectedValue(name, values, 0) {
-		return testResult
-	}
-
-	testResult.Status = status.SUCCESSFUL
-	return testResult
 }
 
 func ValidateLogs(env *environment.MetaData) status.TestResult {
@@ -183,11 +207,13 @@ func ValidateLogs(env *environment.MetaData) status.TestResult {
 	end := time.Now()
 	start := end.Add(time.Duration(-3) * time.Minute)
 	group := fmt.Sprintf("/aws/containerinsights/%s/performance", env.EKSClusterName)
+	testResult.AddIdentifier("log_group", group)
 
 	// need to get the instances used for the EKS cluster
 	eKSInstances, err := awsservice.GetEKSInstances(env.EKSClusterName)
 	if err != nil {
 		log.Println("failed to get EKS instances", err)
+		testResult.Fail("failed to get EKS instances: %v", err)
 		return testResult
 	}
 
@@ -221,6 +247,8 @@ func ValidateLogs(env *environment.MetaData) status.TestResult {
 
 		if err != nil {
 			log.Printf("log validation (%s/%s) failed: %v", group, stream, err)
+			testResult.AddIdentifier("log_stream", stream)
+			testResult.Fail("log validation failed: %v", err)
 			return testResult
 		}
 	}
@@ -239,11 +267,13 @@ func ValidateLogsFrequency(env *environment.MetaData) status.TestResult {
 	end := time.Now().Add(time.Duration(-2) * time.Minute).Truncate(time.Minute)
 	start := end.Add(time.Duration(-1) * time.Minute)
 	group := fmt.Sprintf("/aws/containerinsights/%s/performance", env.EKSClusterName)
+	testResult.AddIdentifier("log_group", group)
 
 	// need to get the instances used for the EKS cluster
 	eKSInstances, err := awsservice.GetEKSInstances(env.EKSClusterName)
 	if err != nil {
 		log.Println("failed to get EKS instances", err)
+		testResult.Fail("failed to get EKS instances: %v", err)
 		return testResult
 	}
 
@@ -257,16 +287,26 @@ func ValidateLogsFrequency(env *environment.MetaData) status.TestResult {
 			actualFrequency, ok := frequencyMap[logType]
 			if !ok {
 				log.Printf("no log with the expected logtype found : %s, start time : %s", logType, start.GoString())
+				testResult.AddIdentifier("log_stream", stream)
+				testResult.AddIdentifier("log_type", logType)
+				testResult.Fail("no log with the expected log type")
 				return testResult
 			}
 			if actualFrequency != expectedFrequency {
 				log.Printf("log frequency validation failed for type: %s, expected: %d, actual: %d, start time: %s", logType, expectedFrequency, actualFrequency, start.GoString())
+				testResult.AddIdentifier("log_stream", stream)
+				testResult.AddIdentifier("log_type", logType)
+				testResult.Fail("unexpected log frequency")
+				testResult.Expected = fmt.Sprint(expectedFrequency)
+				testResult.Observed = fmt.Sprint(actualFrequency)
 				return testResult
 			}
 		}
 
 		if err != nil {
 			log.Printf("log validation (%s/%s) failed: %v, start time : %s", group, stream, err, start)
+			testResult.AddIdentifier("log_stream", stream)
+			testResult.Fail("log validation failed: %v", err)
 			return testResult
 		}
 	}
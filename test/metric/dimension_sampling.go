@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/dimensionsampling"
+)
+
+// coverageKeys are the dimensions the DimensionCoverage counts the series of
+var coverageKeys = []string{"PodName", "NodeName", "Service", "Namespace"}
+
+// DimensionSampler picks the dimension sets to validate with a sampling policy. The dimension sets are sorted
+// before sampling and one random source is shared by every metric, so the same policy and seed pick the same sets
+// from the same metrics.
+type DimensionSampler struct {
+	policy dimensionsampling.Policy
+	random *rand.Rand
+}
+
+func NewDimensionSampler(policy dimensionsampling.Policy) *DimensionSampler {
+	return &DimensionSampler{
+		policy: policy,
+		random: rand.New(rand.NewSource(policy.Seed)),
+	}
+}
+
+// Sample returns the dimension sets to validate in sorted order
+func (s *DimensionSampler) Sample(dimensionSets [][]types.Dimension) [][]types.Dimension {
+	sorted := append([][]types.Dimension{}, dimensionSets...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return formatDimensions(sorted[i]) < formatDimensions(sorted[j])
+	})
+	switch s.policy.Mode {
+	case dimensionsampling.First:
+		return sorted[:minInt(s.policy.Count, len(sorted))]
+	case dimensionsampling.Stratified:
+		return stratify(sorted, s.policy.Key, s.policy.Count)
+	case dimensionsampling.Random:
+		if s.policy.Count >= len(sorted) {
+			return sorted
+		}
+		picked := s.random.Perm(len(sorted))[:s.policy.Count]
+		sort.Ints(picked)
+		sampled := make([][]types.Dimension, 0, len(picked))
+		for _, i := range picked {
+			sampled = append(sampled, sorted[i])
+		}
+		return sampled
+	default:
+		return sorted
+	}
+}
+
+// stratify returns the first count dimension sets of each value of the key, the sets without the key are one stratum
+func stratify(sorted [][]types.Dimension, key string, count int) [][]types.Dimension {
+	picked := make(map[string]int)
+	var sampled [][]types.Dimension
+	for _, dimensionSet := range sorted {
+		value, _ := dimensionValue(dimensionSet, key)
+		if picked[value] < count {
+			picked[value]++
+			sampled = append(sampled, dimensionSet)
+		}
+	}
+	return sampled
+}
+
+func dimensionValue(dimensionSet []types.Dimension, key string) (string, bool) {
+	for _, d := range dimensionSet {
+		if aws.ToString(d.Name) == key {
+			return aws.ToString(d.Value), true
+		}
+	}
+	return "", false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// DimensionCoverage counts the pod, node, service and namespace series available and validated
+type DimensionCoverage struct {
+	available map[string]map[string]struct{}
+	validated map[string]map[string]struct{}
+}
+
+func NewDimensionCoverage() *DimensionCoverage {
+	return &DimensionCoverage{
+		available: make(map[string]map[string]struct{}),
+		validated: make(map[string]map[string]struct{}),
+	}
+}
+
+// Add counts the dimension sets of a metric and the ones sampled for validation
+func (c *DimensionCoverage) Add(available [][]types.Dimension, validated [][]types.Dimension) {
+	addSeries(c.available, available)
+	addSeries(c.validated, validated)
+}
+
+func addSeries(series map[string]map[string]struct{}, dimensionSets [][]types.Dimension) {
+	for _, dimensionSet := range dimensionSets {
+		for _, key := range coverageKeys {
+			value, ok := dimensionValue(dimensionSet, key)
+			if !ok {
+				continue
+			}
+			if series[key] == nil {
+				series[key] = make(map[string]struct{})
+			}
+			series[key][value] = struct{}{}
+		}
+	}
+}
+
+// String summarizes the validated series out of the available ones per dimension, e.g "PodName 3/12"
+func (c *DimensionCoverage) String() string {
+	var summary []string
+	for _, key := range coverageKeys {
+		if len(c.available[key]) == 0 {
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("%s %d/%d", key, len(c.validated[key]), len(c.available[key])))
+	}
+	if len(summary) == 0 {
+		return "no pod, node, service or namespace series"
+	}
+	return strings.Join(summary, ", ")
+}
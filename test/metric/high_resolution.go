@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+)
+
+// HighResolutionPeriods are the periods a high resolution metric is queried at. CloudWatch returns one data point per
+// minute at these periods for a metric stored at standard resolution.
+var HighResolutionPeriods = []int32{1, HighResolutionStatPeriod}
+
+// HighResolutionChecks returns a check per period of HighResolutionPeriods asserting the metric the agent sends every
+// send interval (e.g the metrics_collection_interval of a host plugin under a minute) is stored at high resolution
+// instead of being downsampled
+func HighResolutionChecks(namespace, metricName string, dimensions []types.Dimension, sendInterval time.Duration) []MetricCheck {
+	checks := make([]MetricCheck, 0, len(HighResolutionPeriods))
+	for _, period := range HighResolutionPeriods {
+		checks = append(checks, MetricCheck{
+			Name:        fmt.Sprintf("%s/high_resolution_%ds", metricName, period),
+			Namespace:   namespace,
+			MetricName:  metricName,
+			Dimensions:  dimensions,
+			Stat:        SAMPLE_COUNT,
+			Period:      period,
+			Expectation: ExpectAtLeast(0),
+			Assertions: []SeriesAssertion{
+				NotEmpty(),
+				HighResolutionDataPoints(sendInterval, time.Duration(period)*time.Second),
+			},
+		})
+	}
+	return checks
+}
+
+// ValidateHighResolution validates the metrics sharing the dimensions and send interval are stored at high resolution
+// in one batch (see HighResolutionChecks)
+func ValidateHighResolution(namespace string, metricNames []string, dimensions []types.Dimension, sendInterval time.Duration) []status.TestResult {
+	var checks []MetricCheck
+	for _, metricName := range metricNames {
+		checks = append(checks, HighResolutionChecks(namespace, metricName, dimensions, sendInterval)...)
+	}
+	return BatchValidator{}.Validate(context.Background(), checks)
+}
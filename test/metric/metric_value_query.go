@@ -5,6 +5,7 @@ package metric
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,13 +14,93 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"go.uber.org/multierr"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
 )
 
+const (
+	// MaxQueriesPerRequest is the number of queries GetMetricData accepts in one request
+	MaxQueriesPerRequest = 500
+	// fetchWindow is the time range Fetch queries, ending now
+	fetchWindow = 10 * time.Minute
+)
+
 type MetricValueFetcher struct {
 }
 
+// MetricQuery is a query of a MetricDataRequest, either a metric with a statistic or a metric math expression
+// over the ids of the other queries of the request
+type MetricQuery struct {
+	// Id is generated from the position of the query when empty. Expressions refer to the queries by Id.
+	Id         string
+	Namespace  string
+	MetricName string
+	Dimensions []types.Dimension
+	// Stat is a statistic (e.g Average) or an extended statistic (e.g p99)
+	Stat   Statistics
+	Period int32
+	// Unit only keeps the data points sent with the unit when set
+	Unit types.StandardUnit
+	// Expression is a metric math expression (e.g "m1 / m2 * 100"). The metric fields are ignored when it is set.
+	Expression string
+	Label      string
+	// Hidden queries are only used by expressions and have no result
+	Hidden bool
+}
+
+// StatQueries returns a query per statistic of the metric, with the statistic as id suffix and label
+func StatQueries(id, namespace, metricName string, dimensions []types.Dimension, period int32, stats ...Statistics) []MetricQuery {
+	queries := make([]MetricQuery, 0, len(stats))
+	for _, stat := range stats {
+		queries = append(queries, MetricQuery{
+			Id:         queryId(id + "_" + string(stat)),
+			Namespace:  namespace,
+			MetricName: metricName,
+			Dimensions: dimensions,
+			Stat:       stat,
+			Period:     period,
+			Label:      string(stat),
+		})
+	}
+	return queries
+}
+
+// MetricDataRequest is a GetMetricData request over an explicit time range
+type MetricDataRequest struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Queries   []MetricQuery
+	// ScanBy orders the data points of the results, oldest first when empty
+	ScanBy types.ScanBy
+	// AllowPartialData logs results with the PartialData status instead of failing
+	AllowPartialData bool
+}
+
+// DataPoint is a value of a MetricDataResult with its timestamp
+type DataPoint struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// MetricDataResult is the result of a query of a MetricDataRequest, merged across the GetMetricData pages
+type MetricDataResult struct {
+	Id         string
+	Label      string
+	DataPoints []DataPoint
+	StatusCode types.StatusCode
+	Messages   []string
+}
+
+// Values returns the values of the data points
+func (r *MetricDataResult) Values() MetricValues {
+	values := make(MetricValues, 0, len(r.DataPoints))
+	for _, dataPoint := range r.DataPoints {
+		values = append(values, dataPoint.Value)
+	}
+	return values
+}
+
 func logDimensions(dims []types.Dimension) {
 	log.Printf("\tDimensions:\n")
 	for _, d := range dims {
@@ -29,50 +110,185 @@ func logDimensions(dims []types.Dimension) {
 	}
 }
 
+// Fetch returns the values of the metric statistic over the last 10 minutes, latest first. Partial data is logged.
 func (n *MetricValueFetcher) Fetch(namespace, metricName string, metricSpecificDimensions []types.Dimension, stat Statistics, metricQueryPeriod int32) (MetricValues, error) {
-	dimensions := metricSpecificDimensions
 	log.Println("Metric query input dimensions")
-	logDimensions(dimensions)
-	metricToFetch := types.Metric{
-		Namespace:  aws.String(namespace),
-		MetricName: aws.String(metricName),
-		Dimensions: dimensions,
-	}
-
-	metricDataQueries := []types.MetricDataQuery{
-		{
-			MetricStat: &types.MetricStat{
-				Metric: &metricToFetch,
-				Period: &metricQueryPeriod,
-				Stat:   aws.String(string(stat)),
-			},
-			Id: aws.String(strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(metricName, "-", "_"), ".", "_"))),
-		},
-	}
+	logDimensions(metricSpecificDimensions)
+	log.Printf("Metric data input: namespace %v, name %v, stat %v, period %v",
+		namespace, metricName, stat, metricQueryPeriod)
 
 	endTime := time.Now()
-	startTime := subtractMinutes(endTime, 10)
-	getMetricDataInput := cloudwatch.GetMetricDataInput{
-		StartTime:         &startTime,
-		EndTime:           &endTime,
-		MetricDataQueries: metricDataQueries,
+	results, err := n.Query(context.Background(), MetricDataRequest{
+		StartTime: endTime.Add(-fetchWindow),
+		EndTime:   endTime,
+		Queries: []MetricQuery{{
+			Id:         queryId(metricName),
+			Namespace:  namespace,
+			MetricName: metricName,
+			Dimensions: metricSpecificDimensions,
+			Stat:       stat,
+			Period:     metricQueryPeriod,
+		}},
+		ScanBy:           types.ScanByTimestampDescending,
+		AllowPartialData: true,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Metric data input: namespace %v, name %v, stat %v, period %v",
-		namespace, metricName, stat, metricQueryPeriod)
+	result := results[0].Values()
+	log.Printf("Metric values are : %s", fmt.Sprint(result))
+	return result, nil
+}
+
+// Query runs the GetMetricData request through every page and returns a result per query that is not hidden, in
+// the order of the queries. A result with the PartialData status fails the query unless AllowPartialData is set,
+// any other status but Complete fails it.
+func (n *MetricValueFetcher) Query(ctx context.Context, request MetricDataRequest) ([]*MetricDataResult, error) {
+	if len(request.Queries) == 0 {
+		return nil, errors.New("metric data request has no query")
+	}
+	if len(request.Queries) > MaxQueriesPerRequest {
+		return nil, fmt.Errorf("metric data request has %d queries, GetMetricData accepts %d", len(request.Queries), MaxQueriesPerRequest)
+	}
+	if !request.StartTime.Before(request.EndTime) {
+		return nil, fmt.Errorf("metric data request start time %v is not before end time %v", request.StartTime, request.EndTime)
+	}
 
-	output, err := awsservice.CwmClient.GetMetricData(context.Background(), &getMetricDataInput)
+	scanBy := request.ScanBy
+	if scanBy == "" {
+		scanBy = types.ScanByTimestampAscending
+	}
+	input := cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(request.StartTime),
+		EndTime:           aws.Time(request.EndTime),
+		MetricDataQueries: make([]types.MetricDataQuery, 0, len(request.Queries)),
+		ScanBy:            scanBy,
+	}
+	var results []*MetricDataResult
+	resultsById := make(map[string]*MetricDataResult)
+	for i, query := range request.Queries {
+		dataQuery := query.toMetricDataQuery(i)
+		input.MetricDataQueries = append(input.MetricDataQueries, dataQuery)
+		if !query.Hidden {
+			result := &MetricDataResult{Id: *dataQuery.Id, Label: query.Label}
+			results = append(results, result)
+			resultsById[result.Id] = result
+		}
+	}
+
+	for {
+		output, err := awsservice.CwmClient.GetMetricData(ctx, &input)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting metric data %v", err)
+		}
+		for _, message := range output.Messages {
+			log.Printf("Metric data message: %s %s", aws.ToString(message.Code), aws.ToString(message.Value))
+		}
+		for _, dataResult := range output.MetricDataResults {
+			result, ok := resultsById[aws.ToString(dataResult.Id)]
+			if !ok {
+				continue
+			}
+			result.merge(dataResult)
+		}
+		// nil or empty nextToken means there is no more data to be fetched
+		if output.NextToken == nil || *output.NextToken == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var err error
+	for _, result := range results {
+		err = multierr.Append(err, result.checkStatus(request.AllowPartialData))
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Error getting metric data %v", err)
+		return nil, err
 	}
+	return results, nil
+}
 
-	result := output.MetricDataResults[0].Values
-	log.Printf("Metric values are : %s", fmt.Sprint(result))
+func (q MetricQuery) toMetricDataQuery(index int) types.MetricDataQuery {
+	id := q.Id
+	if id == "" {
+		id = fmt.Sprint("q", index)
+	}
+	dataQuery := types.MetricDataQuery{
+		Id:         aws.String(id),
+		ReturnData: aws.Bool(!q.Hidden),
+	}
+	if q.Label != "" {
+		dataQuery.Label = aws.String(q.Label)
+	}
+	if q.Expression != "" {
+		dataQuery.Expression = aws.String(q.Expression)
+		if q.Period > 0 {
+			dataQuery.Period = aws.Int32(q.Period)
+		}
+		return dataQuery
+	}
+	dataQuery.MetricStat = &types.MetricStat{
+		Metric: &types.Metric{
+			Namespace:  aws.String(q.Namespace),
+			MetricName: aws.String(q.MetricName),
+			Dimensions: q.Dimensions,
+		},
+		Period: aws.Int32(q.Period),
+		Stat:   aws.String(string(q.Stat)),
+		Unit:   q.Unit,
+	}
+	return dataQuery
+}
 
-	return result, nil
+// merge appends the data points of a GetMetricData page. The status of the last page is the status of the result.
+func (r *MetricDataResult) merge(dataResult types.MetricDataResult) {
+	if dataResult.Label != nil && r.Label == "" {
+		r.Label = *dataResult.Label
+	}
+	for i, value := range dataResult.Values {
+		if i < len(dataResult.Timestamps) {
+			r.DataPoints = append(r.DataPoints, DataPoint{Timestamp: dataResult.Timestamps[i], Value: value})
+		}
+	}
+	r.StatusCode = dataResult.StatusCode
+	for _, message := range dataResult.Messages {
+		r.Messages = append(r.Messages, fmt.Sprintf("%s %s", aws.ToString(message.Code), aws.ToString(message.Value)))
+	}
 }
 
-func subtractMinutes(fromTime time.Time, minutes int) time.Time {
-	tenMinutes := time.Duration(-1*minutes) * time.Minute
-	return fromTime.Add(tenMinutes)
+func (r *MetricDataResult) checkStatus(allowPartialData bool) error {
+	switch r.StatusCode {
+	case types.StatusCodeComplete:
+		return nil
+	case types.StatusCodePartialData:
+		if allowPartialData {
+			log.Printf("Metric data result %s has partial data: %v", r.Id, r.Messages)
+			return nil
+		}
+		return fmt.Errorf("metric data result %s has partial data: %v", r.Id, r.Messages)
+	case "":
+		return fmt.Errorf("metric data query %s has no result", r.Id)
+	default:
+		return fmt.Errorf("metric data result %s has status %q: %v", r.Id, r.StatusCode, r.Messages)
+	}
+}
+
+// queryId turns a name into a GetMetricData query id, which starts with a lowercase letter and only has letters,
+// digits and underscores
+func queryId(name string) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '_'
+		}
+	}, name)
+	if id == "" || id[0] < 'a' || id[0] > 'z' {
+		id = "m_" + id
+	}
+	return id
 }
@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+)
+
+// NamespaceAllowlist is every metric a test expects in its namespace and dimension scope, so the metrics the agent
+// should not have emitted are caught as well as the missing ones
+type NamespaceAllowlist struct {
+	Namespace string
+	// Scope are the dimensions every listed metric has (e.g the InstanceId of the test host), since the namespace is
+	// shared with the other test runs
+	Scope []types.Dimension
+	// Expected are the metric name patterns (e.g cpu_usage_*) each matching at least one listed metric
+	Expected []string
+	// Optional are the metric name patterns allowed but not required
+	Optional []string
+	// MaxDimensionSets bounds the unique dimension sets of a metric name, the metrics not in it are unbounded
+	MaxDimensionSets map[string]int
+}
+
+// Validate lists the metrics of the namespace in scope and returns the unexpected metrics, missing metrics and
+// dimension set cardinality results
+func (a NamespaceAllowlist) Validate() []status.TestResult {
+	unexpected := status.TestResult{Name: a.Namespace + "/unexpected_metrics", Status: status.FAILED}
+	missing := status.TestResult{Name: a.Namespace + "/missing_metrics", Status: status.FAILED}
+	cardinality := status.TestResult{Name: a.Namespace + "/dimension_sets", Status: status.FAILED}
+	results := []*status.TestResult{&unexpected, &missing, &cardinality}
+	for _, result := range results {
+		result.AddIdentifier("namespace", a.Namespace)
+		result.AddIdentifier("dimensions", formatDimensions(a.Scope))
+	}
+	testResults := func() []status.TestResult {
+		return []status.TestResult{unexpected, missing, cardinality}
+	}
+
+	for _, pattern := range append(append([]string{}, a.Expected...), a.Optional...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			for _, result := range results {
+				result.Fail("invalid metric name pattern %q: %v", pattern, err)
+			}
+			return testResults()
+		}
+	}
+	metrics, err := (&Fetcher{}).Fetch(a.Namespace, "", a.Scope)
+	if err != nil {
+		for _, result := range results {
+			result.Fail("failed to list metrics: %v", err)
+		}
+		return testResults()
+	}
+
+	dimensionSets := make(map[string]map[string]struct{})
+	for _, m := range metrics {
+		name := aws.ToString(m.MetricName)
+		if dimensionSets[name] == nil {
+			dimensionSets[name] = make(map[string]struct{})
+		}
+		dimensionSets[name][formatDimensions(m.Dimensions)] = struct{}{}
+	}
+	names := make([]string, 0, len(dimensionSets))
+	for name := range dimensionSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unexpectedNames []string
+	for _, name := range names {
+		if !matchesAny(name, a.Expected) && !matchesAny(name, a.Optional) {
+			unexpectedNames = append(unexpectedNames, name)
+		}
+	}
+	unexpected.Expected = strings.Join(append(append([]string{}, a.Expected...), a.Optional...), ",")
+	unexpected.Observed = strings.Join(names, ",")
+	if len(unexpectedNames) > 0 {
+		unexpected.Fail("unexpected metrics %v", unexpectedNames)
+	} else {
+		unexpected.Status = status.SUCCESSFUL
+	}
+
+	var missingPatterns []string
+	for _, pattern := range a.Expected {
+		if !anyMatches(pattern, names) {
+			missingPatterns = append(missingPatterns, pattern)
+		}
+	}
+	missing.Expected = strings.Join(a.Expected, ",")
+	missing.Observed = unexpected.Observed
+	if len(missingPatterns) > 0 {
+		missing.Fail("no metric matches %v", missingPatterns)
+	} else {
+		missing.Status = status.SUCCESSFUL
+	}
+
+	var exploded []string
+	for _, name := range names {
+		if maxDimensionSets, ok := a.MaxDimensionSets[name]; ok && len(dimensionSets[name]) > maxDimensionSets {
+			exploded = append(exploded, fmt.Sprintf("%s has %d of at most %d", name, len(dimensionSets[name]), maxDimensionSets))
+		}
+	}
+	if len(a.MaxDimensionSets) > 0 {
+		bounded := make([]string, 0, len(a.MaxDimensionSets))
+		for name, maxDimensionSets := range a.MaxDimensionSets {
+			bounded = append(bounded, fmt.Sprintf("%s: %d", name, maxDimensionSets))
+		}
+		sort.Strings(bounded)
+		cardinality.Expected = "at most " + strings.Join(bounded, ", ") + " dimension sets"
+	}
+	if len(exploded) > 0 {
+		cardinality.Fail("too many dimension sets: %s", strings.Join(exploded, ", "))
+	} else {
+		cardinality.Status = status.SUCCESSFUL
+	}
+
+	for _, result := range testResults() {
+		if result.Status == status.FAILED {
+			log.Printf("Namespace allowlist %s failed: %s", result.Name, result.Reason)
+		}
+	}
+	return testResults()
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func anyMatches(pattern string, names []string) bool {
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Series is the data points of a metric, oldest first
+type Series []DataPoint
+
+// Series returns the data points of the result oldest first, whatever the ScanBy of the request
+func (r *MetricDataResult) Series() Series {
+	series := append(Series{}, r.DataPoints...)
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Timestamp.Before(series[j].Timestamp)
+	})
+	return series
+}
+
+// SeriesAssertion checks what a healthy series of a metric looks like (e.g a counter never decreases)
+type SeriesAssertion func(series Series) error
+
+// NotEmpty asserts the series has data points
+func NotEmpty() SeriesAssertion {
+	return func(series Series) error {
+		if len(series) == 0 {
+			return errors.New("series has no data points")
+		}
+		return nil
+	}
+}
+
+// WithinRange asserts every value is in [min, max]
+func WithinRange(min, max float64) SeriesAssertion {
+	return func(series Series) error {
+		for _, dataPoint := range series {
+			if dataPoint.Value < min || dataPoint.Value > max {
+				return fmt.Errorf("value %v at %v is not within [%v, %v]", dataPoint.Value, dataPoint.Timestamp, min, max)
+			}
+		}
+		return nil
+	}
+}
+
+// NonNegative asserts no value is negative
+func NonNegative() SeriesAssertion {
+	return WithinRange(0, math.Inf(1))
+}
+
+// Percentage asserts every value is a percentage in [0, 100]
+func Percentage() SeriesAssertion {
+	return WithinRange(0, 100)
+}
+
+// MonotonicNonDecreasing asserts a counter never decreases
+func MonotonicNonDecreasing() SeriesAssertion {
+	return func(series Series) error {
+		for i := 1; i < len(series); i++ {
+			if series[i].Value < series[i-1].Value {
+				return fmt.Errorf("counter decreased from %v at %v to %v at %v",
+					series[i-1].Value, series[i-1].Timestamp, series[i].Value, series[i].Timestamp)
+			}
+		}
+		return nil
+	}
+}
+
+// RateWithin asserts the per second rate of a counter between consecutive data points is in [min, max]
+func RateWithin(min, max float64) SeriesAssertion {
+	return func(series Series) error {
+		for i := 1; i < len(series); i++ {
+			seconds := series[i].Timestamp.Sub(series[i-1].Timestamp).Seconds()
+			if seconds <= 0 {
+				continue
+			}
+			rate := (series[i].Value - series[i-1].Value) / seconds
+			if rate < min || rate > max {
+				return fmt.Errorf("rate %v/s from %v to %v is not within [%v, %v]", rate, series[i-1].Timestamp, series[i].Timestamp, min, max)
+			}
+		}
+		return nil
+	}
+}
+
+// StableWithin asserts a gauge stays within the tolerance (e.g 0.05 for 5%) of its average
+func StableWithin(tolerance float64) SeriesAssertion {
+	return func(series Series) error {
+		if len(series) == 0 {
+			return nil
+		}
+		sum := 0.0
+		for _, dataPoint := range series {
+			sum += dataPoint.Value
+		}
+		average := sum / float64(len(series))
+		bound := math.Abs(average) * tolerance
+		for _, dataPoint := range series {
+			if math.Abs(dataPoint.Value-average) > bound {
+				return fmt.Errorf("value %v at %v is not within %v%% of the average %v", dataPoint.Value, dataPoint.Timestamp, tolerance*100, average)
+			}
+		}
+		return nil
+	}
+}
+
+// NoGapsLongerThan asserts consecutive data points are at most the number of periods apart
+func NoGapsLongerThan(periods int, period time.Duration) SeriesAssertion {
+	return func(series Series) error {
+		maxGap := time.Duration(periods) * period
+		for i := 1; i < len(series); i++ {
+			if gap := series[i].Timestamp.Sub(series[i-1].Timestamp); gap > maxGap {
+				return fmt.Errorf("gap of %v from %v to %v is longer than %d periods of %v", gap, series[i-1].Timestamp, series[i].Timestamp, periods, period)
+			}
+		}
+		return nil
+	}
+}
+
+// SampleCountPerPeriod asserts a SampleCount series has a sample per collection interval in each period, give or
+// take one sample for the flushes at the period boundaries. The first and last periods are only partly covered by
+// the agent run and are not checked.
+func SampleCountPerPeriod(collectionInterval, period time.Duration) SeriesAssertion {
+	expected := float64(period / collectionInterval)
+	return func(series Series) error {
+		for i := 1; i < len(series)-1; i++ {
+			if math.Abs(series[i].Value-expected) > 1 {
+				return fmt.Errorf("sample count %v at %v does not match %v samples per %v", series[i].Value, series[i].Timestamp, expected, period)
+			}
+		}
+		return nil
+	}
+}
+
+// HighResolutionDataPoints asserts a series queried at a period under a minute has the data points of a metric sent
+// every send interval and stored at high resolution: one per period or send interval, whichever is longer. A metric
+// stored at standard resolution only has one data point per minute. The median spacing of the data points is checked,
+// and the data points per minute when the series covers full minutes (the first and last minutes are only partly
+// covered by the agent run).
+func HighResolutionDataPoints(sendInterval, period time.Duration) SeriesAssertion {
+	bucket := sendInterval
+	if period > bucket {
+		bucket = period
+	}
+	expected := int(time.Minute / bucket)
+	tolerance := expected / 10
+	if tolerance < 1 {
+		tolerance = 1
+	}
+	// a standard resolution metric has one data point per minute, which is never within the tolerance
+	lowerBound := expected - tolerance
+	if lowerBound < 2 {
+		lowerBound = 2
+	}
+	upperBound := expected + tolerance
+	return func(series Series) error {
+		if expected < 2 {
+			return fmt.Errorf("a metric sent every %v has no more than one data point per minute at any resolution", sendInterval)
+		}
+		if len(series) < 2 {
+			return fmt.Errorf("series has %d data points, at least 2 are needed to check the resolution", len(series))
+		}
+		gaps := make([]time.Duration, 0, len(series)-1)
+		for i := 1; i < len(series); i++ {
+			gaps = append(gaps, series[i].Timestamp.Sub(series[i-1].Timestamp))
+		}
+		sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+		if median := gaps[len(gaps)/2]; median > bucket+bucket/2 {
+			return fmt.Errorf("data points are a median %v apart, expected %v for a high resolution metric sent every %v queried every %v",
+				median, bucket, sendInterval, period)
+		}
+
+		var minutes []time.Time
+		dataPoints := make(map[time.Time]int)
+		for _, dataPoint := range series {
+			minute := dataPoint.Timestamp.Truncate(time.Minute)
+			if _, ok := dataPoints[minute]; !ok {
+				minutes = append(minutes, minute)
+			}
+			dataPoints[minute]++
+		}
+		for i := 1; i < len(minutes)-1; i++ {
+			if count := dataPoints[minutes[i]]; count < lowerBound || count > upperBound {
+				return fmt.Errorf("%d data points in the minute of %v, expected %d for a high resolution metric sent every %v queried every %v",
+					count, minutes[i], expected, sendInterval, period)
+			}
+		}
+		return nil
+	}
+}
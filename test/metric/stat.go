@@ -3,6 +3,8 @@
 
 package metric
 
+import "fmt"
+
 type Statistics string
 type MetricValues []float64
 
@@ -15,3 +17,15 @@ const (
 	HighResolutionStatPeriod            = 10
 	MinuteStatPeriod                    = 60
 )
+
+// Extended statistics
+const (
+	P50 Statistics = "p50"
+	P90 Statistics = "p90"
+	P99 Statistics = "p99"
+)
+
+// Percentile returns the extended statistic of the percentile (e.g p99.9)
+func Percentile(percentile float64) Statistics {
+	return Statistics(fmt.Sprintf("p%g", percentile))
+}
@@ -6,6 +6,8 @@
 package metric
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"strings"
 	"time"
@@ -36,44 +38,32 @@ var (
 )
 
 func ValidateStatsdMetric(dimFactory dimension.Factory, namespace string, dimensionKey string, metricName string, expectedValue float64, runDuration time.Duration, sendInterval time.Duration) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
-	instructions := []dimension.Instruction{
-		{
-			Key:   dimensionKey,
-			Value: dimension.UnknownDimensionValue(),
-		},
-		{
-			Key:   "key",
-			Value: dimension.ExpectedDimensionValue{Value: aws.String("value")},
-		},
-	}
+	testResult := NewTestResult(namespace, metricName)
 	split := strings.Split(metricName, "_")
 	if len(split) != 3 {
 		log.Printf("unexpected metric name format, %s", metricName)
 	}
 	metricType := split[1]
-	instructions = append(instructions, dimension.Instruction{
-		// CWA adds this metric_type dimension.
-		Key:   "metric_type",
-		Value: dimension.ExpectedDimensionValue{Value: aws.String(metricType)},
-	})
-	dims, failed := dimFactory.GetDimensions(instructions)
+	dims, failed := dimFactory.GetDimensions(statsdDimensions(dimensionKey, metricType))
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	AddDimensions(&testResult, dims)
 	fetcher := MetricValueFetcher{}
 	// Check average.
 	values, err := fetcher.Fetch(namespace, metricName, dims, AVERAGE, HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch %s: %v", AVERAGE, err)
 		return testResult
 	}
 
 	lowerBound := int(runDuration/statsdMetricsAggregationInterval) - 4
 	if len(values) < lowerBound {
 		log.Printf("fail: lowerBound %v, actual %v", lowerBound, len(values))
+		testResult.Fail("too few datapoints")
+		testResult.Expected = fmt.Sprintf("at least %d datapoints", lowerBound)
+		testResult.Observed = fmt.Sprintf("%d datapoints", len(values))
 		return testResult
 	}
 	// Counters get summed up over the metrics_collection_interval.
@@ -81,6 +71,9 @@ func ValidateStatsdMetric(dimFactory dimension.Factory, namespace string, dimens
 		expectedValue *= float64(statsdMetricsCollectionInterval / sendInterval)
 	}
 	if !IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, float64(expectedValue)) {
+		testResult.Fail("%s below the expected value", AVERAGE)
+		testResult.Expected = fmt.Sprintf("%s >= %v", AVERAGE, expectedValue)
+		testResult.Observed = fmt.Sprint(values)
 		return testResult
 	}
 	// Check aggregation by checking sample count.
@@ -93,13 +86,61 @@ func ValidateStatsdMetric(dimFactory dimension.Factory, namespace string, dimens
 	}
 	values, err = fetcher.Fetch(namespace, metricName, dims, SAMPLE_COUNT, HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch %s: %v", SAMPLE_COUNT, err)
 		return testResult
 	}
 	// Skip check on the last value.
 	values = values[:len(values)-1]
 	if !IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, float64(expectedSampleCount)) {
+		testResult.Fail("%s below the expected value", SAMPLE_COUNT)
+		testResult.Expected = fmt.Sprintf("%s >= %v", SAMPLE_COUNT, float64(expectedSampleCount))
+		testResult.Observed = fmt.Sprint(values)
 		return testResult
 	}
 	testResult.Status = status.SUCCESSFUL
 	return testResult
 }
+
+// ValidateStatsdHighResolution validates the statsd metrics the agent sends every metrics_aggregation_interval are
+// stored at high resolution (see HighResolutionChecks)
+func ValidateStatsdHighResolution(dimFactory dimension.Factory, namespace string, dimensionKey string, metricNames []string) []status.TestResult {
+	var checks []MetricCheck
+	var failedResults []status.TestResult
+	for _, metricName := range metricNames {
+		metricType := ""
+		if split := strings.Split(metricName, "_"); len(split) == 3 {
+			metricType = split[1]
+		}
+		dims, failed := dimFactory.GetDimensions(statsdDimensions(dimensionKey, metricType))
+		metricChecks := HighResolutionChecks(namespace, metricName, dims, statsdMetricsAggregationInterval)
+		if len(failed) > 0 {
+			for _, check := range metricChecks {
+				result := check.newTestResult()
+				result.Fail("failed to resolve dimensions %v", failed)
+				failedResults = append(failedResults, result)
+			}
+			continue
+		}
+		checks = append(checks, metricChecks...)
+	}
+	return append(failedResults, BatchValidator{}.Validate(context.Background(), checks)...)
+}
+
+// statsdDimensions are the dimensions of a statsd metric sent by the test with the key:value tag
+func statsdDimensions(dimensionKey string, metricType string) []dimension.Instruction {
+	return []dimension.Instruction{
+		{
+			Key:   dimensionKey,
+			Value: dimension.UnknownDimensionValue(),
+		},
+		{
+			Key:   "key",
+			Value: dimension.ExpectedDimensionValue{Value: aws.String("value")},
+		},
+		{
+			// CWA adds this metric_type dimension.
+			Key:   "metric_type",
+			Value: dimension.ExpectedDimensionValue{Value: aws.String(metricType)},
+		},
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+)
+
+// NewTestResult returns a failed test result for the metric, identified by its namespace and metric name. The
+// validators add the dimensions once they are resolved and mark the result successful once every check passed.
+func NewTestResult(namespace, metricName string) status.TestResult {
+	testResult := status.TestResult{
+		Name:   metricName,
+		Status: status.FAILED,
+	}
+	testResult.AddIdentifier("namespace", namespace)
+	testResult.AddIdentifier("metric_name", metricName)
+	return testResult
+}
+
+// AddDimensions records the resolved dimensions of the metric the test result looked at
+func AddDimensions(testResult *status.TestResult, dimensions []types.Dimension) {
+	testResult.AddIdentifier("dimensions", formatDimensions(dimensions))
+}
+
+// ValidateValues checks the values with IsAllValuesGreaterThanOrEqualToExpectedValue and fails the test result with
+// the expected and observed values when they do not match
+func ValidateValues(testResult *status.TestResult, metricName string, values []float64, expectedValue float64) bool {
+	if IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, expectedValue) {
+		return true
+	}
+	testResult.Expected = "non-negative values"
+	if expectedValue > 0 {
+		testResult.Expected = fmt.Sprintf("non-negative values averaging %v ±10%%", expectedValue)
+	}
+	if len(values) == 0 {
+		testResult.Fail("no values")
+		testResult.Observed = "no values"
+		return false
+	}
+	testResult.Fail("values out of the expected range")
+	testResult.Observed = fmt.Sprint(values)
+	return false
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+)
+
+// UnitCheck is a metric expected to have every data point sent with the Unit
+type UnitCheck struct {
+	// Name is the name of the test result, <metric name>/unit when empty
+	Name       string
+	Namespace  string
+	MetricName string
+	Dimensions []types.Dimension
+	Unit       types.StandardUnit
+	// Period is MinuteStatPeriod when not set
+	Period int32
+}
+
+// ValidateUnits returns a test result per check, in the order of the checks. The samples of each period are counted
+// with and without the unit filter of GetMetricData in the same request, a check fails when the metric has no
+// samples or some of its samples were sent with another unit.
+func (v BatchValidator) ValidateUnits(ctx context.Context, checks []UnitCheck) []status.TestResult {
+	results := make([]status.TestResult, len(checks))
+	for i, check := range checks {
+		results[i] = check.newTestResult()
+	}
+	startTime, endTime := v.timeRange()
+	// each check takes a query with and a query without the unit filter
+	v.forEachBatch(len(checks), MaxQueriesPerRequest/2, func(start, end int) {
+		v.validateUnitBatch(ctx, startTime, endTime, checks[start:end], results[start:end])
+	})
+	return results
+}
+
+func (v BatchValidator) validateUnitBatch(ctx context.Context, startTime, endTime time.Time, checks []UnitCheck, results []status.TestResult) {
+	queries := make([]MetricQuery, 0, 2*len(checks))
+	for i, check := range checks {
+		period := check.Period
+		if period == 0 {
+			period = MinuteStatPeriod
+		}
+		query := MetricQuery{
+			Namespace:  check.Namespace,
+			MetricName: check.MetricName,
+			Dimensions: check.Dimensions,
+			Stat:       SAMPLE_COUNT,
+			Period:     period,
+		}
+		all, withUnit := query, query
+		all.Id = fmt.Sprint("a", i)
+		withUnit.Id = fmt.Sprint("u", i)
+		withUnit.Unit = check.Unit
+		queries = append(queries, all, withUnit)
+	}
+	log.Printf("Validating the units of %d metrics with one GetMetricData request", len(checks))
+	dataResults, err := v.Fetcher.Query(ctx, MetricDataRequest{
+		StartTime:        startTime,
+		EndTime:          endTime,
+		Queries:          queries,
+		AllowPartialData: true,
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Fail("failed to fetch metric sample counts: %v", err)
+		}
+		return
+	}
+
+	for i := range checks {
+		all, withUnit := dataResults[2*i], dataResults[2*i+1]
+		if (all.StatusCode == types.StatusCodePartialData || withUnit.StatusCode == types.StatusCodePartialData) && !v.AllowPartialData {
+			results[i].Fail("metric sample counts are partial: %v", append(all.Messages, withUnit.Messages...))
+			continue
+		}
+		total, matching, err := compareSampleCounts(all.Series(), withUnit.Series())
+		results[i].Observed = fmt.Sprintf("%v of %v samples", matching, total)
+		if total == 0 {
+			results[i].Fail("metric has no samples")
+			continue
+		}
+		if err != nil {
+			results[i].Fail("%v", err)
+			continue
+		}
+		results[i].Status = status.SUCCESSFUL
+	}
+}
+
+// compareSampleCounts returns the samples of the metric, the samples sent with the unit and the first period where
+// some samples were sent with another unit
+func compareSampleCounts(all, withUnit Series) (total float64, matching float64, err error) {
+	matchingByTimestamp := make(map[time.Time]float64, len(withUnit))
+	for _, dataPoint := range withUnit {
+		matchingByTimestamp[dataPoint.Timestamp] = dataPoint.Value
+		matching += dataPoint.Value
+	}
+	for _, dataPoint := range all {
+		total += dataPoint.Value
+		if err == nil && matchingByTimestamp[dataPoint.Timestamp] != dataPoint.Value {
+			err = fmt.Errorf("%v of %v samples at %v were sent with the unit",
+				matchingByTimestamp[dataPoint.Timestamp], dataPoint.Value, dataPoint.Timestamp)
+		}
+	}
+	return total, matching, err
+}
+
+func (c UnitCheck) newTestResult() status.TestResult {
+	name := c.Name
+	if name == "" {
+		name = c.MetricName + "/unit"
+	}
+	result := status.TestResult{
+		Name:     name,
+		Status:   status.FAILED,
+		Expected: string(c.Unit),
+	}
+	result.AddIdentifier("namespace", c.Namespace)
+	result.AddIdentifier("metric_name", c.MetricName)
+	result.AddIdentifier("unit", string(c.Unit))
+	result.AddIdentifier("dimensions", formatDimensions(c.Dimensions))
+	return result
+}
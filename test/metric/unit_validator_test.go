@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareSampleCounts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := func(i int) time.Time {
+		return start.Add(time.Duration(i) * time.Minute)
+	}
+	testCases := map[string]struct {
+		all, withUnit   Series
+		total, matching float64
+		wantErr         bool
+	}{
+		"NoSamples": {},
+		"AllWithUnit": {
+			all:      Series{{Timestamp: minute(0), Value: 6}, {Timestamp: minute(1), Value: 6}},
+			withUnit: Series{{Timestamp: minute(0), Value: 6}, {Timestamp: minute(1), Value: 6}},
+			total:    12,
+			matching: 12,
+		},
+		"SomeWithAnotherUnit": {
+			all:      Series{{Timestamp: minute(0), Value: 6}, {Timestamp: minute(1), Value: 6}},
+			withUnit: Series{{Timestamp: minute(0), Value: 6}, {Timestamp: minute(1), Value: 4}},
+			total:    12,
+			matching: 10,
+			wantErr:  true,
+		},
+		"PeriodWithoutUnit": {
+			all:      Series{{Timestamp: minute(0), Value: 6}, {Timestamp: minute(1), Value: 6}},
+			withUnit: Series{{Timestamp: minute(0), Value: 6}},
+			total:    12,
+			matching: 6,
+			wantErr:  true,
+		},
+		"NoneWithUnit": {
+			all:     Series{{Timestamp: minute(0), Value: 6}},
+			total:   6,
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			total, matching, err := compareSampleCounts(testCase.all, testCase.withUnit)
+			assert.Equal(t, testCase.total, total)
+			assert.Equal(t, testCase.matching, matching)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
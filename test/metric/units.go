@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric
+
+import (
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// defaultUnits are the units the agent sends the host plugin measurements with when the agent config sets no unit.
+// The first pattern matching the metric name wins.
+var defaultUnits = []struct {
+	pattern string
+	unit    types.StandardUnit
+}{
+	{"cpu_usage_*", types.StandardUnitPercent},
+	{"cpu_time_*", types.StandardUnitNone},
+	{"mem_*_percent", types.StandardUnitPercent},
+	{"mem_*", types.StandardUnitBytes},
+	{"swap_used_percent", types.StandardUnitPercent},
+	{"swap_*", types.StandardUnitBytes},
+	{"disk_used_percent", types.StandardUnitPercent},
+	{"disk_inodes_*", types.StandardUnitCount},
+	{"disk_*", types.StandardUnitBytes},
+	{"diskio_*_bytes", types.StandardUnitBytes},
+	{"diskio_*_time", types.StandardUnitMilliseconds},
+	{"diskio_*", types.StandardUnitCount},
+	{"net_bytes_*", types.StandardUnitBytes},
+	{"net_*", types.StandardUnitCount},
+	{"netstat_*", types.StandardUnitCount},
+	{"processes_*", types.StandardUnitCount},
+}
+
+// DefaultUnit returns the unit the agent sends a host plugin metric with when the agent config sets no unit
+func DefaultUnit(metricName string) (types.StandardUnit, bool) {
+	for _, defaultUnit := range defaultUnits {
+		if ok, _ := path.Match(defaultUnit.pattern, metricName); ok {
+			return defaultUnit.unit, true
+		}
+	}
+	return "", false
+}
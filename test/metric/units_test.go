@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultUnit(t *testing.T) {
+	testCases := map[string]types.StandardUnit{
+		"cpu_usage_idle":          types.StandardUnitPercent,
+		"cpu_time_active":         types.StandardUnitNone,
+		"mem_used_percent":        types.StandardUnitPercent,
+		"mem_available":           types.StandardUnitBytes,
+		"swap_used_percent":       types.StandardUnitPercent,
+		"swap_free":               types.StandardUnitBytes,
+		"disk_used_percent":       types.StandardUnitPercent,
+		"disk_inodes_free":        types.StandardUnitCount,
+		"disk_used":               types.StandardUnitBytes,
+		"diskio_read_bytes":       types.StandardUnitBytes,
+		"diskio_write_time":       types.StandardUnitMilliseconds,
+		"diskio_reads":            types.StandardUnitCount,
+		"net_bytes_sent":          types.StandardUnitBytes,
+		"net_packets_recv":        types.StandardUnitCount,
+		"netstat_tcp_listen":      types.StandardUnitCount,
+		"processes_running":       types.StandardUnitCount,
+		"diskio_iops_in_progress": types.StandardUnitCount,
+	}
+	for metricName, want := range testCases {
+		unit, ok := DefaultUnit(metricName)
+		assert.True(t, ok, metricName)
+		assert.Equal(t, want, unit, metricName)
+	}
+
+	// metrics of the other plugins have no default unit
+	for _, metricName := range []string{"procstat_cpu_usage", "ethtool_bw_in_allowance_exceeded", ""} {
+		_, ok := DefaultUnit(metricName)
+		assert.False(t, ok, metricName)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 type OneAggregateDimensionTestRunner struct {
@@ -44,24 +45,24 @@ func (t *OneAggregateDimensionTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *OneAggregateDimensionTestRunner) validateNoAppendDimensionMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace("MetricAggregateDimensionTest"), metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch("MetricAggregateDimensionTest", metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace("MetricAggregateDimensionTest"), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !isAllValuesGreaterThanOrEqualToZero(metricName, values) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 type AggregationDimensionsTestRunner struct {
@@ -39,7 +40,7 @@ func (t *AggregationDimensionsTestRunner) Validate() status.TestGroupResult {
 			instructions = append(instructions, i)
 		}
 		dd, _ := t.DimensionFactory.GetDimensions(instructions)
-		values, err := f.Fetch("TestAggregationDimensions",
+		values, err := f.Fetch(common.RunNamespace("TestAggregationDimensions"),
 			testCase.metricName, dd, metric.AVERAGE,
 			metric.HighResolutionStatPeriod)
 		if err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 const (
@@ -47,6 +48,7 @@ func (t *DropOriginalMetricsTestRunner) Validate() status.TestGroupResult {
 		}
 		results = append(results, result)
 	}
+	results = append(results, t.validateAllowlist()...)
 
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
@@ -71,7 +73,7 @@ func (t *DropOriginalMetricsTestRunner) validate(want expectation) error {
 	fetcher := metric.MetricValueFetcher{}
 	dimensions, _ := t.DimensionFactory.GetDimensions(want.dimensions)
 	for _, metricName := range want.metricNames {
-		values, err := fetcher.Fetch(testNamespace, metricName, dimensions, metric.AVERAGE, metric.HighResolutionStatPeriod)
+		values, err := fetcher.Fetch(common.RunNamespace(testNamespace), metricName, dimensions, metric.AVERAGE, metric.HighResolutionStatPeriod)
 		if err != nil {
 			return err
 		}
@@ -85,6 +87,26 @@ func (t *DropOriginalMetricsTestRunner) validate(want expectation) error {
 	return nil
 }
 
+// validateAllowlist checks the agent emitted nothing but the metrics of the agent config on the test host, so a dropped
+// metric coming back under another dimension set fails the test
+func (t *DropOriginalMetricsTestRunner) validateAllowlist() []status.TestResult {
+	names := []string{"Allowlist"}
+	dimensions, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
+		{
+			Key:   "InstanceId",
+			Value: dimension.UnknownDimensionValue(),
+		},
+	})
+	if len(failed) > 0 {
+		return status.FailAll(names, "failed to resolve dimensions %v", failed)
+	}
+	allowlist, err := test_runner.AgentConfigAllowlist(t, dimensions)
+	if err != nil {
+		return status.FailAll(names, "failed to read the expected metrics of the agent config: %v", err)
+	}
+	return allowlist.Validate()
+}
+
 func (t *DropOriginalMetricsTestRunner) testCases() map[string][]expectation {
 	return map[string][]expectation{
 		"None": {
@@ -6,6 +6,7 @@
 package metric_dimension
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,6 +15,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 type GlobalAppendDimensionsTestRunner struct {
@@ -48,10 +50,7 @@ func (t *GlobalAppendDimensionsTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *GlobalAppendDimensionsTestRunner) validateGlobalAppendDimensionMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace("MetricGlobalAppendDimensionTest"), metricName)
 
 	expDims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -73,17 +72,20 @@ func (t *GlobalAppendDimensionsTestRunner) validateGlobalAppendDimensionMetric(m
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, expDims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch("MetricGlobalAppendDimensionTest", metricName, expDims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace("MetricGlobalAppendDimensionTest"), metricName, expDims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !isAllValuesGreaterThanOrEqualToZero(metricName, values) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -101,11 +103,19 @@ func (t *GlobalAppendDimensionsTestRunner) validateGlobalAppendDimensionMetric(m
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
 
-	values, err = fetcher.Fetch("MetricGlobalAppendDimensionTest", metricName, dropDims, metric.AVERAGE, metric.HighResolutionStatPeriod)
-	if err != nil || len(values) != 0 {
+	values, err = fetcher.Fetch(common.RunNamespace("MetricGlobalAppendDimensionTest"), metricName, dropDims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
+		return testResult
+	}
+	if len(values) != 0 {
+		testResult.Fail("metric is still sent with the host dimension")
+		testResult.Expected = "no values"
+		testResult.Observed = fmt.Sprint(values)
 		return testResult
 	}
 
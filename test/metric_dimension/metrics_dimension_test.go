@@ -7,7 +7,6 @@ package metric_dimension
 
 import (
 	"fmt"
-	"log"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -29,6 +28,7 @@ func (suite *MetricsAppendDimensionTestSuite) SetupSuite() {
 
 func (suite *MetricsAppendDimensionTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("MetricsAppendDimensionTestSuite")
 	fmt.Println(">>>> Finished MetricAppendDimensionTestSuite")
 }
 
@@ -56,8 +56,10 @@ func getTestRunners(env *environment.MetaData) []*test_runner.TestRunner {
 
 func (suite *MetricsAppendDimensionTestSuite) TestAllInSuite() {
 	env := environment.GetEnvironmentMetaData()
-	for _, testRunner := range getTestRunners(env) {
-		suite.AddToSuiteResult(testRunner.Run())
+	ctx, cancel := test_runner.Context(suite.T())
+	defer cancel()
+	for _, testRunner := range test_runner.SelectTestRunnersByTags(env, getTestRunners(env)) {
+		suite.AddToSuiteResult(testRunner.Run(ctx))
 	}
 	suite.Assert().Equal(status.SUCCESSFUL, suite.Result.GetStatus(), "Metric Append Dimension Test Suite Failed")
 }
@@ -69,18 +71,3 @@ func (suite *MetricsAppendDimensionTestSuite) AddToSuiteResult(r status.TestGrou
 func TestMetricsAppendDimensionTestSuite(t *testing.T) {
 	suite.Run(t, new(MetricsAppendDimensionTestSuite))
 }
-
-func isAllValuesGreaterThanOrEqualToZero(metricName string, values []float64) bool {
-	if len(values) == 0 {
-		log.Printf("No values found %v", metricName)
-		return false
-	}
-	for _, value := range values {
-		if value < 0 {
-			log.Printf("Values are not all greater than or equal to zero for %v", metricName)
-			return false
-		}
-	}
-	log.Printf("Values are all greater than or equal to zero for %v", metricName)
-	return true
-}
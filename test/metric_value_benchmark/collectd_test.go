@@ -6,6 +6,8 @@
 package metric_value_benchmark
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -41,12 +43,16 @@ func (t *CollectDTestRunner) GetTestName() string {
 	return "CollectD"
 }
 
+func (t *CollectDTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("collectd")}
+}
+
 func (t *CollectDTestRunner) GetAgentConfigFileName() string {
 	return "collectd_config.json"
 }
 
-func (t *CollectDTestRunner) SetupAfterAgentRun() error {
-	return common.SendCollectDMetrics(2, time.Second, t.GetAgentRunDuration())
+func (t *CollectDTestRunner) SetupAfterAgentRun(ctx context.Context) error {
+	return common.SendCollectDMetrics(ctx, 2, time.Second, t.GetAgentRunDuration(), nil)
 }
 
 func (t *CollectDTestRunner) GetMeasuredMetrics() []string {
@@ -54,10 +60,7 @@ func (t *CollectDTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *CollectDTestRunner) validateCollectDMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
 	instructions := []dimension.Instruction{
 		{
@@ -82,12 +85,15 @@ func (t *CollectDTestRunner) validateCollectDMetric(metricName string) status.Te
 
 	dims, failed := t.DimensionFactory.GetDimensions(instructions)
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
@@ -103,10 +109,13 @@ func (t *CollectDTestRunner) validateCollectDMetric(metricName string) status.Te
 	if len(values) < lowerBound || len(values) > upperBound {
 		log.Printf("fail: lowerBound %v, upperBound %v, actual %v",
 			lowerBound, upperBound, len(values))
+		testResult.Fail("unexpected number of datapoints")
+		testResult.Expected = fmt.Sprintf("%d to %d datapoints", lowerBound, upperBound)
+		testResult.Observed = fmt.Sprintf("%d datapoints", len(values))
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 1) {
+	if !metric.ValidateValues(&testResult, metricName, values, 1) {
 		return testResult
 	}
 
@@ -64,10 +64,7 @@ func (t *ContainerInsightsTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *ContainerInsightsTestRunner) validateContainerInsightsMetrics(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult("ECS/ContainerInsights", metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -85,16 +82,19 @@ func (t *ContainerInsightsTestRunner) validateContainerInsightsMetrics(metricNam
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
 	values, err := fetcher.Fetch("ECS/ContainerInsights", metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -114,9 +114,12 @@ func validateLogsForContainerInsights(e *environment.MetaData) status.TestResult
 	now := time.Now()
 	group := fmt.Sprintf("/aws/ecs/containerinsights/%s/performance", e.EcsClusterName)
 
+	testResult.AddIdentifier("log_group", group)
+
 	// need to derive the container Instance ID first
 	containers, err := awsservice.GetContainerInstances(e.EcsClusterArn)
 	if err != nil {
+		testResult.Fail("failed to get the container instances: %v", err)
 		return testResult
 	}
 
@@ -136,6 +139,8 @@ func validateLogsForContainerInsights(e *environment.MetaData) status.TestResult
 
 		if err != nil {
 			log.Printf("log validation (%s/%s) for container (%s) failed: %v", group, stream, container.ContainerInstanceId, err)
+			testResult.AddIdentifier("log_stream", stream)
+			testResult.Fail("log validation failed: %v", err)
 			return testResult
 		}
 	}
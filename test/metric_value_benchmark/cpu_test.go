@@ -6,9 +6,10 @@
 package metric_value_benchmark
 
 import (
-	"log"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
@@ -23,15 +24,9 @@ type CPUTestRunner struct {
 var _ test_runner.ITestRunner = (*CPUTestRunner)(nil)
 
 func (t *CPUTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := t.GetMeasuredMetrics()
-	testResults := make([]status.TestResult, len(metricsToFetch))
-	for i, metricName := range metricsToFetch {
-		testResults[i] = t.validateCpuMetric(metricName)
-	}
-
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
-		TestResults: testResults,
+		TestResults: t.validateCpuMetrics(t.GetMeasuredMetrics()),
 	}
 }
 
@@ -39,21 +34,31 @@ func (t *CPUTestRunner) GetTestName() string {
 	return "CPU"
 }
 
+func (t *CPUTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("cpu")}
+}
+
 func (t *CPUTestRunner) GetAgentConfigFileName() string {
 	return "cpu_config.json"
 }
 
+func (t *CPUTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
 func (t *CPUTestRunner) GetMeasuredMetrics() []string {
 	// time_active gets renamed with agent_config/cpu_config.json
 	return append(metric.CpuMetrics[1:], "cpu_time_active_renamed")
 }
 
-func (t *CPUTestRunner) validateCpuMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+// GetExpectedUnits returns the units of the metrics of agent_configs/cpu_config.json
+func (t *CPUTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(t)
+}
 
+// validateCpuMetrics fetches the values of every metric with a single GetMetricData request and checks the metrics
+// collected every 10 seconds are stored at high resolution with the expected units
+func (t *CPUTestRunner) validateCpuMetrics(metricNames []string) []status.TestResult {
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
 			Key:   "InstanceId",
@@ -64,26 +69,19 @@ func (t *CPUTestRunner) validateCpuMetric(metricName string) status.TestResult {
 			Value: dimension.ExpectedDimensionValue{Value: aws.String("cpu-total")},
 		},
 	})
-
 	if len(failed) > 0 {
-		return testResult
-	}
-
-	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
-	log.Printf("metric values are %v", values)
-	if err != nil {
-		log.Printf("err: %v\n", err)
-		return testResult
+		return status.FailAll(metricNames, "failed to resolve dimensions %v", failed)
 	}
+	results := metric.ValidateSeries(namespace(), metricNames, dims, metric.AVERAGE, metric.HighResolutionStatPeriod, cpuSeriesAssertions)
+	results = append(results, test_runner.ValidateHighResolution(t, dims)...)
+	return append(results, test_runner.ValidateUnits(t, dims)...)
+}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
-		return testResult
+// cpuSeriesAssertions states the healthy series of the cpu metrics: the usages are percentages and the times are
+// the seconds spent over each collection interval
+func cpuSeriesAssertions(metricName string) []metric.SeriesAssertion {
+	if strings.HasPrefix(metricName, "cpu_usage_") {
+		return []metric.SeriesAssertion{metric.Percentage()}
 	}
-
-	// TODO: Range test with >0 and <100
-	// TODO: Range test: which metric to get? api reference check. should I get average or test every single datapoint for 10 minutes? (and if 90%> of them are in range, we are good)
-
-	testResult.Status = status.SUCCESSFUL
-	return testResult
+	return []metric.SeriesAssertion{metric.NonNegative()}
 }
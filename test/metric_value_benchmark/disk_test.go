@@ -6,7 +6,7 @@
 package metric_value_benchmark
 
 import (
-	"log"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
@@ -21,15 +21,9 @@ type DiskTestRunner struct {
 var _ test_runner.ITestRunner = (*DiskTestRunner)(nil)
 
 func (t *DiskTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := t.GetMeasuredMetrics()
-	testResults := make([]status.TestResult, len(metricsToFetch))
-	for i, metricName := range metricsToFetch {
-		testResults[i] = t.ValidateDiskMetric(metricName)
-	}
-
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
-		TestResults: testResults,
+		TestResults: t.validateDiskMetrics(t.GetMeasuredMetrics()),
 	}
 }
 
@@ -37,10 +31,18 @@ func (t *DiskTestRunner) GetTestName() string {
 	return "Disk"
 }
 
+func (t *DiskTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("disk")}
+}
+
 func (t *DiskTestRunner) GetAgentConfigFileName() string {
 	return "disk_config.json"
 }
 
+func (t *DiskTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
 func (t *DiskTestRunner) GetMeasuredMetrics() []string {
 	return []string{
 		"disk_free",
@@ -53,35 +55,37 @@ func (t *DiskTestRunner) GetMeasuredMetrics() []string {
 	}
 }
 
-func (t *DiskTestRunner) ValidateDiskMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+// GetExpectedUnits returns the units of the metrics of agent_configs/disk_config.json
+func (t *DiskTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(t)
+}
 
+// validateDiskMetrics fetches the values of every metric with a single GetMetricData request and checks the metrics
+// collected every 10 seconds are stored at high resolution with the expected units
+func (t *DiskTestRunner) validateDiskMetrics(metricNames []string) []status.TestResult {
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
 			Key:   "InstanceId",
 			Value: dimension.UnknownDimensionValue(),
 		},
 	})
-
 	if len(failed) > 0 {
-		return testResult
-	}
-
-	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
-
-	log.Printf("metric values are %v", values)
-	if err != nil {
-		return testResult
+		return status.FailAll(metricNames, "failed to resolve dimensions %v", failed)
 	}
+	results := metric.ValidateSeries(namespace(), metricNames, dims, metric.AVERAGE, metric.HighResolutionStatPeriod, diskSeriesAssertions)
+	results = append(results, test_runner.ValidateHighResolution(t, dims)...)
+	return append(results, test_runner.ValidateUnits(t, dims)...)
+}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
-		return testResult
+// diskSeriesAssertions states the healthy series of the disk metrics: used_percent is in [0, 100] and the sizes of
+// the file systems stay the same during the test
+func diskSeriesAssertions(metricName string) []metric.SeriesAssertion {
+	switch metricName {
+	case "disk_used_percent":
+		return []metric.SeriesAssertion{metric.Percentage()}
+	case "disk_total", "disk_inodes_total":
+		return []metric.SeriesAssertion{metric.StableWithin(0.01)}
+	default:
+		return []metric.SeriesAssertion{metric.NonNegative()}
 	}
-
-	testResult.Status = status.SUCCESSFUL
-	return testResult
 }
@@ -7,6 +7,7 @@ package metric_value_benchmark
 
 import (
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
@@ -20,6 +21,18 @@ type DiskIOTestRunner struct {
 
 var _ test_runner.ITestRunner = (*DiskIOTestRunner)(nil)
 
+// diskIODimensions are the dimensions the metrics are validated with
+var diskIODimensions = []dimension.Instruction{
+	{
+		Key:   "name",
+		Value: dimension.ExpectedDimensionValue{Value: aws.String("nvme0n1")},
+	},
+	{
+		Key:   "InstanceId",
+		Value: dimension.UnknownDimensionValue(),
+	},
+}
+
 func (m *DiskIOTestRunner) Validate() status.TestGroupResult {
 	metricsToFetch := m.GetMeasuredMetrics()
 	testResults := make([]status.TestResult, len(metricsToFetch))
@@ -27,6 +40,10 @@ func (m *DiskIOTestRunner) Validate() status.TestGroupResult {
 		testResults[i] = m.validateDiskMetric(name)
 	}
 
+	if dims, failed := m.DimensionFactory.GetDimensions(diskIODimensions); len(failed) == 0 {
+		testResults = append(testResults, test_runner.ValidateUnits(m, dims)...)
+	}
+
 	return status.TestGroupResult{
 		Name:        m.GetTestName(),
 		TestResults: testResults,
@@ -37,44 +54,48 @@ func (m *DiskIOTestRunner) GetTestName() string {
 	return "DiskIO"
 }
 
+func (m *DiskIOTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("diskio")}
+}
+
 func (m *DiskIOTestRunner) GetAgentConfigFileName() string {
 	return "diskio_config.json"
 }
 
+func (m *DiskIOTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
 func (m *DiskIOTestRunner) GetMeasuredMetrics() []string {
 	return []string{
 		"diskio_iops_in_progress", "diskio_io_time", "diskio_reads", "diskio_read_bytes", "diskio_read_time",
 		"diskio_writes", "diskio_write_bytes", "diskio_write_time"}
 }
 
+// GetExpectedUnits returns the units of the metrics of agent_configs/diskio_config.json
+func (m *DiskIOTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(m)
+}
+
 func (m *DiskIOTestRunner) validateDiskMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
-	dims, failed := m.DimensionFactory.GetDimensions([]dimension.Instruction{
-		{
-			Key:   "name",
-			Value: dimension.ExpectedDimensionValue{aws.String("nvme0n1")},
-		},
-		{
-			Key:   "InstanceId",
-			Value: dimension.UnknownDimensionValue(),
-		},
-	})
+	dims, failed := m.DimensionFactory.GetDimensions(diskIODimensions)
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -6,6 +6,7 @@
 package metric_value_benchmark
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -44,10 +45,7 @@ func (e *EKSDaemonTestRunner) Validate() status.TestGroupResult {
 }
 
 func (e *EKSDaemonTestRunner) validateInstanceMetrics(name string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   name,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(metric.ContainerInsightsNamespace, name)
 
 	dims, failed := e.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -57,6 +55,7 @@ func (e *EKSDaemonTestRunner) validateInstanceMetrics(name string) status.TestRe
 	})
 	if len(failed) > 0 {
 		log.Println("failed to get dimensions")
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
 
@@ -67,11 +66,13 @@ func (e *EKSDaemonTestRunner) validateInstanceMetrics(name string) status.TestRe
 		metrics, err := listFetcher.Fetch(metric.ContainerInsightsNamespace, name, dims)
 		if err != nil {
 			log.Println("failed to fetch metric list", err)
+			testResult.Fail("failed to list metrics: %v", err)
 			return testResult
 		}
 
 		if len(metrics) < 1 {
 			log.Println("metric list is empty")
+			testResult.Fail("metric is not listed")
 			return testResult
 		}
 
@@ -90,13 +91,15 @@ func (e *EKSDaemonTestRunner) validateInstanceMetrics(name string) status.TestRe
 	}
 
 	valueFetcher := metric.MetricValueFetcher{}
+	metric.AddDimensions(&testResult, dims)
 	values, err := valueFetcher.Fetch(metric.ContainerInsightsNamespace, name, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
 		log.Println("failed to fetch metrics", err)
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(name, values, 0) {
+	if !metric.ValidateValues(&testResult, name, values, 0) {
 		return testResult
 	}
 
@@ -147,7 +150,7 @@ func (e *EKSDaemonTestRunner) GetMeasuredMetrics() []string {
 
 func (t *EKSDaemonTestRunner) SetAgentConfig(config test_runner.AgentConfig) {}
 
-func (e *EKSDaemonTestRunner) SetupAfterAgentRun() error {
+func (e *EKSDaemonTestRunner) SetupAfterAgentRun(context.Context) error {
 	return nil
 }
 
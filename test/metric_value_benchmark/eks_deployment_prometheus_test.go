@@ -37,10 +37,7 @@ func (e *EKSDeploymentTestRunner) Validate() status.TestGroupResult {
 }
 
 func (e *EKSDeploymentTestRunner) validateInstanceMetrics(name string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   name,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult("ContainerInsights/Prometheus", name)
 
 	dims, failed := e.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -55,17 +52,20 @@ func (e *EKSDeploymentTestRunner) validateInstanceMetrics(name string) status.Te
 
 	if len(failed) > 0 {
 		log.Println("failed to get dimensions")
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
 
 	fetcher := metric.MetricValueFetcher{}
+	metric.AddDimensions(&testResult, dims)
 	values, err := fetcher.Fetch("ContainerInsights/Prometheus", name, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
 		log.Println("failed to fetch metrics", err)
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(name, values, 0) {
+	if !metric.ValidateValues(&testResult, name, values, 0) {
 		return testResult
 	}
 
@@ -6,8 +6,12 @@
 package metric_value_benchmark
 
 import (
+	"context"
 	_ "embed"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -20,8 +24,19 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
+const (
+	emfHighResolutionMetricName = "EMFHighResolutionCounter"
+	// emfHighResolutionSendInterval is how often the EMF metric with a StorageResolution of 1 second is sent
+	emfHighResolutionSendInterval = time.Second
+	emfListenerAddress            = "127.0.0.1:25888"
+	// emfDialRetryInterval is how long to wait before connecting again while the agent EMF listener is not up
+	emfDialRetryInterval = time.Second
+)
+
 type EMFTestRunner struct {
 	test_runner.BaseTestRunner
+	// sendResult receives the error sendHighResolutionEMF stopped with, nil once the agent run ended
+	sendResult chan error
 }
 
 //go:embed agent_resources/emf_counter.json
@@ -37,6 +52,7 @@ func (t *EMFTestRunner) Validate() status.TestGroupResult {
 	}
 
 	testResults = append(testResults, validateEMFLogs("MetricValueBenchmarkTest", awsservice.GetInstanceId()))
+	testResults = append(testResults, t.validateEMFHighResolution()...)
 
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
@@ -48,11 +64,20 @@ func (t *EMFTestRunner) GetTestName() string {
 	return "EMF"
 }
 
+func (t *EMFTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("emf"), test_runner.TagNeedsRoot}
+}
+
 func (t *EMFTestRunner) GetAgentConfigFileName() string {
 	return "emf_config.json"
 }
 
-func (t *EMFTestRunner) SetupAfterAgentRun() error {
+func (t *EMFTestRunner) SetupAfterAgentRun(ctx context.Context) error {
+	t.sendResult = make(chan error, 1)
+	go func() {
+		t.sendResult <- t.sendHighResolutionEMF(ctx)
+	}()
+
 	// EC2 Image Builder creates a bash script that sends emf format to cwagent at port 8125
 	// The bash script is at /etc/emf.sh
 	// TOKEN=$(curl -X PUT "http://169.254.169.254/latest/api/token" -H "X-aws-ec2-metadata-token-ttl-seconds: 21600")
@@ -70,15 +95,69 @@ func (t *EMFTestRunner) SetupAfterAgentRun() error {
 	return common.RunCommands(startEMFCommands)
 }
 
+// sendHighResolutionEMF sends an EMF metric with a StorageResolution of 1 second every emfHighResolutionSendInterval
+// until the agent run ends. The logs go to their own log stream so validateEMFLogs only sees the logs of /etc/emf.sh.
+func (t *EMFTestRunner) sendHighResolutionEMF(ctx context.Context) error {
+	conn, err := dialEMFListener(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	instanceId := awsservice.GetInstanceId()
+	ticker := time.NewTicker(emfHighResolutionSendInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			emfLog, _ := json.Marshal(map[string]interface{}{
+				"_aws": map[string]interface{}{
+					"Timestamp":     time.Now().UnixMilli(),
+					"LogGroupName":  "MetricValueBenchmarkTest",
+					"LogStreamName": instanceId + "-high-resolution",
+					"CloudWatchMetrics": []map[string]interface{}{{
+						"Namespace":  benchmarkNamespace,
+						"Dimensions": [][]string{{"Type", "InstanceId"}},
+						"Metrics": []map[string]interface{}{
+							{"Name": emfHighResolutionMetricName, "Unit": "Count", "StorageResolution": 1},
+						},
+					}},
+				},
+				"Type":                      "HighResolutionCounter",
+				"InstanceId":                instanceId,
+				emfHighResolutionMetricName: 5,
+			})
+			if _, err = conn.Write(append(emfLog, '\n')); err != nil {
+				return fmt.Errorf("failed to send the high resolution EMF metric: %w", err)
+			}
+		}
+	}
+}
+
+// dialEMFListener connects to the agent EMF listener, retrying until the agent started listening or the agent run ended
+func dialEMFListener(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", emfListenerAddress)
+		if err == nil {
+			return conn, nil
+		}
+		log.Printf("Agent EMF listener is not up yet: %v", err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to the agent EMF listener before the agent run ended: %w", err)
+		case <-time.After(emfDialRetryInterval):
+		}
+	}
+}
+
 func (t *EMFTestRunner) GetMeasuredMetrics() []string {
 	return []string{"EMFCounter"}
 }
 
 func (t *EMFTestRunner) validateEMFMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(benchmarkNamespace, metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -92,16 +171,19 @@ func (t *EMFTestRunner) validateEMFMetric(metricName string) status.TestResult {
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(benchmarkNamespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 5) {
+	if !metric.ValidateValues(&testResult, metricName, values, 5) {
 		return testResult
 	}
 
@@ -109,12 +191,39 @@ func (t *EMFTestRunner) validateEMFMetric(metricName string) status.TestResult {
 	return testResult
 }
 
+// validateEMFHighResolution checks the EMF metric sent with a StorageResolution of 1 second is stored at high resolution
+func (t *EMFTestRunner) validateEMFHighResolution() []status.TestResult {
+	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
+		{
+			Key:   "InstanceId",
+			Value: dimension.UnknownDimensionValue(),
+		},
+		{
+			Key:   "Type",
+			Value: dimension.ExpectedDimensionValue{Value: aws.String("HighResolutionCounter")},
+		},
+	})
+	if len(failed) > 0 {
+		return status.FailAll([]string{emfHighResolutionMetricName}, "failed to resolve dimensions %v", failed)
+	}
+	// the sender stops once the agent run ended, which it did before Validate
+	if t.sendResult != nil {
+		if err := <-t.sendResult; err != nil {
+			return status.FailAll([]string{emfHighResolutionMetricName}, "%v", err)
+		}
+	}
+	return metric.ValidateHighResolution(benchmarkNamespace, []string{emfHighResolutionMetricName}, dims, emfHighResolutionSendInterval)
+}
+
 func validateEMFLogs(group, stream string) status.TestResult {
 	testResult := status.TestResult{
 		Name:   "emf-logs",
 		Status: status.FAILED,
 	}
 
+	testResult.AddIdentifier("log_group", group)
+	testResult.AddIdentifier("log_stream", stream)
+
 	now := time.Now()
 	err := awsservice.ValidateLogs(
 		group,
@@ -129,6 +238,7 @@ func validateEMFLogs(group, stream string) status.TestResult {
 	)
 	if err != nil {
 		log.Printf("log validation (%s/%s) failed: %v", group, stream, err)
+		testResult.Fail("log validation failed: %v", err)
 		return testResult
 	}
 
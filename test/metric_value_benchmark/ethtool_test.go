@@ -40,6 +40,10 @@ func (m *EthtoolTestRunner) GetTestName() string {
 	return "Ethtool"
 }
 
+func (m *EthtoolTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("ethtool")}
+}
+
 func (m *EthtoolTestRunner) GetAgentConfigFileName() string {
 	return "ethtool_config.json"
 }
@@ -51,13 +55,11 @@ func (m *EthtoolTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (m *EthtoolTestRunner) validateEthtoolMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
 	ifaces, err := net.Interfaces()
 	if err != nil {
+		testResult.Fail("failed to list the network interfaces: %v", err)
 		return testResult
 	}
 
@@ -87,17 +89,20 @@ func (m *EthtoolTestRunner) validateEthtoolMetric(metricName string) status.Test
 	}
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -39,6 +39,10 @@ func (t *JMXKafkaTestRunner) GetTestName() string {
 	return "JMXKafka"
 }
 
+func (t *JMXKafkaTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("jmx"), test_runner.TagNeedsRoot, test_runner.TagNeedsNetwork}
+}
+
 func (t *JMXKafkaTestRunner) GetAgentConfigFileName() string {
 	return "jmx_kafka_config.json"
 }
@@ -121,10 +125,7 @@ func (t *JMXKafkaTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *JMXKafkaTestRunner) validateJMXMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(jmxNamespace(), metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -134,18 +135,21 @@ func (t *JMXKafkaTestRunner) validateJMXMetric(metricName string) status.TestRes
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(jmxNamespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(jmxNamespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	log.Printf("metric values are %v", values)
 	if err != nil {
 		log.Printf("err: %v\n", err)
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
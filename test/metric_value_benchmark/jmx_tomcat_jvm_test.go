@@ -16,7 +16,10 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
-const jmxNamespace = "MetricValueBenchmarkJMXTest"
+// jmxNamespace returns the namespace the JMX agent configs render {{ .Namespace `MetricValueBenchmarkJMXTest` }} to
+func jmxNamespace() string {
+	return common.RunNamespace("MetricValueBenchmarkJMXTest")
+}
 
 type JMXTomcatJVMTestRunner struct {
 	test_runner.BaseTestRunner
@@ -41,6 +44,10 @@ func (t *JMXTomcatJVMTestRunner) GetTestName() string {
 	return "JMXTomcatJVM"
 }
 
+func (t *JMXTomcatJVMTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("jmx")}
+}
+
 func (t *JMXTomcatJVMTestRunner) GetAgentConfigFileName() string {
 	return "jmx_tomcat_jvm_config.json"
 }
@@ -96,10 +103,7 @@ func (t *JMXTomcatJVMTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *JMXTomcatJVMTestRunner) validateJMXMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(jmxNamespace(), metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -109,18 +113,21 @@ func (t *JMXTomcatJVMTestRunner) validateJMXMetric(metricName string) status.Tes
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(jmxNamespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(jmxNamespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	log.Printf("metric values are %v", values)
 	if err != nil {
 		log.Printf("err: %v\n", err)
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, -1) {
+	if !metric.ValidateValues(&testResult, metricName, values, -1) {
 		return testResult
 	}
 
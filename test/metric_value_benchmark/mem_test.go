@@ -6,6 +6,10 @@
 package metric_value_benchmark
 
 import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
@@ -19,15 +23,13 @@ type MemTestRunner struct {
 var _ test_runner.ITestRunner = (*MemTestRunner)(nil)
 
 func (m *MemTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := m.GetMeasuredMetrics()
-	testResults := make([]status.TestResult, len(metricsToFetch))
-	for i, name := range metricsToFetch {
-		testResults[i] = m.validateMemMetric(name)
+	metricNames, err := test_runner.MeasuredMetricsFromAgentConfig(m)
+	if err != nil {
+		return test_runner.AgentConfigFailure(m, err)
 	}
-
 	return status.TestGroupResult{
 		Name:        m.GetTestName(),
-		TestResults: testResults,
+		TestResults: m.validateMemMetrics(metricNames),
 	}
 }
 
@@ -35,43 +37,56 @@ func (m *MemTestRunner) GetTestName() string {
 	return "Mem"
 }
 
+func (m *MemTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("mem")}
+}
+
 func (m *MemTestRunner) GetAgentConfigFileName() string {
 	return "mem_config.json"
 }
 
+func (m *MemTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
+// GetMeasuredMetrics returns the measurements of agent_configs/mem_config.json, none when it cannot be read (Validate
+// fails then)
 func (m *MemTestRunner) GetMeasuredMetrics() []string {
-	return []string{
-		"mem_active", "mem_available", "mem_available_percent", "mem_buffered", "mem_cached",
-		"mem_free", "mem_inactive", "mem_total", "mem_used", "mem_used_percent"}
+	metricNames, _ := test_runner.MeasuredMetricsFromAgentConfig(m)
+	return metricNames
 }
 
-func (m *MemTestRunner) validateMemMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+// GetExpectedUnits returns the units of the metrics of agent_configs/mem_config.json
+func (m *MemTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(m)
+}
 
+// validateMemMetrics fetches the values of every metric with a single GetMetricData request and checks the metrics
+// collected every 10 seconds are stored at high resolution with the expected units
+func (m *MemTestRunner) validateMemMetrics(metricNames []string) []status.TestResult {
 	dims, failed := m.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
 			Key:   "InstanceId",
 			Value: dimension.UnknownDimensionValue(),
 		},
 	})
-
 	if len(failed) > 0 {
-		return testResult
-	}
-
-	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
-	if err != nil {
-		return testResult
+		return status.FailAll(metricNames, "failed to resolve dimensions %v", failed)
 	}
+	results := metric.ValidateSeries(namespace(), metricNames, dims, metric.AVERAGE, metric.HighResolutionStatPeriod, memSeriesAssertions)
+	results = append(results, test_runner.ValidateHighResolution(m, dims)...)
+	return append(results, test_runner.ValidateUnits(m, dims)...)
+}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
-		return testResult
+// memSeriesAssertions states the healthy series of the mem metrics: the percentages are in [0, 100] and the total
+// memory of the host does not change
+func memSeriesAssertions(metricName string) []metric.SeriesAssertion {
+	switch {
+	case strings.HasSuffix(metricName, "_percent"):
+		return []metric.SeriesAssertion{metric.Percentage()}
+	case metricName == "mem_total":
+		return []metric.SeriesAssertion{metric.StableWithin(0)}
+	default:
+		return []metric.SeriesAssertion{metric.NonNegative()}
 	}
-
-	testResult.Status = status.SUCCESSFUL
-	return testResult
 }
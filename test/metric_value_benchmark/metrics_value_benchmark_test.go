@@ -8,7 +8,6 @@ package metric_value_benchmark
 import (
 	"fmt"
 	"log"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -19,9 +18,16 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/test_runner"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
-const namespace = "MetricValueBenchmarkTest"
+// benchmarkNamespace is the namespace of the agent configs before the test run renders them (see namespace())
+const benchmarkNamespace = "MetricValueBenchmarkTest"
+
+// namespace returns the namespace the agent configs render {{ .Namespace `MetricValueBenchmarkTest` }} to
+func namespace() string {
+	return common.RunNamespace(benchmarkNamespace)
+}
 
 type MetricBenchmarkTestSuite struct {
 	suite.Suite
@@ -34,6 +40,7 @@ func (suite *MetricBenchmarkTestSuite) SetupSuite() {
 
 func (suite *MetricBenchmarkTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("MetricBenchmarkTestSuite")
 	fmt.Println(">>>> Finished MetricBenchmarkTestSuite")
 }
 
@@ -108,7 +115,7 @@ func getEc2TestRunners(env *environment.MetaData) []*test_runner.TestRunner {
 			{TestRunner: &DiskIOTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
 			{TestRunner: &NetTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
 			{TestRunner: &EthtoolTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
-			{TestRunner: &EMFTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
+			{TestRunner: &EMFTestRunner{BaseTestRunner: test_runner.BaseTestRunner{DimensionFactory: factory}}},
 			{TestRunner: &SwapTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
 			{TestRunner: &ProcessesTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
 			{TestRunner: &CollectDTestRunner{test_runner.BaseTestRunner{DimensionFactory: factory}}},
@@ -135,9 +142,13 @@ func (suite *MetricBenchmarkTestSuite) TestAllInSuite() {
 		}
 	default: // EC2 tests
 		log.Println("Environment compute type is EC2")
-		for _, testRunner := range getEc2TestRunners(env) {
-			if shouldRunEC2Test(env, testRunner) {
-				suite.AddToSuiteResult(testRunner.Run())
+		testRunners := test_runner.SelectTestRunners(env, getEc2TestRunners(env))
+		ctx, cancel := test_runner.Context(suite.T())
+		defer cancel()
+		// Test runners only reading host metrics share an agent run
+		for _, testRunnerGroup := range test_runner.GroupTestRunners(testRunners) {
+			for _, testGroupResult := range testRunnerGroup.Run(ctx) {
+				suite.AddToSuiteResult(testGroupResult)
 			}
 		}
 	}
@@ -152,17 +163,3 @@ func (suite *MetricBenchmarkTestSuite) AddToSuiteResult(r status.TestGroupResult
 func TestMetricValueBenchmarkSuite(t *testing.T) {
 	suite.Run(t, new(MetricBenchmarkTestSuite))
 }
-
-func shouldRunEC2Test(env *environment.MetaData, t *test_runner.TestRunner) bool {
-	if env.EC2PluginTests == nil && env.ExcludedTests == nil {
-		return true // default behavior is to run all tests
-	}
-	_, shouldRun := env.EC2PluginTests[strings.ToLower(t.TestRunner.GetTestName())]
-	_, shouldExclude := env.ExcludedTests[strings.ToLower(t.TestRunner.GetTestName())]
-	if shouldRun {
-		return true
-	} else if len(env.ExcludedTests) != 0 {
-		return !shouldExclude
-	}
-	return false
-}
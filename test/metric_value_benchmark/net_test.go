@@ -7,6 +7,7 @@ package metric_value_benchmark
 
 import (
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
@@ -22,15 +23,9 @@ type NetTestRunner struct {
 var _ test_runner.ITestRunner = (*NetTestRunner)(nil)
 
 func (m *NetTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := m.GetMeasuredMetrics()
-	testResults := make([]status.TestResult, len(metricsToFetch))
-	for i, name := range metricsToFetch {
-		testResults[i] = m.validateNetMetric(name)
-	}
-
 	return status.TestGroupResult{
 		Name:        m.GetTestName(),
-		TestResults: testResults,
+		TestResults: m.validateNetMetrics(m.GetMeasuredMetrics()),
 	}
 }
 
@@ -46,22 +41,32 @@ func (m *NetTestRunner) GetTestName() string {
 	return "Net"
 }
 
+func (m *NetTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("net"), test_runner.TagNeedsRoot}
+}
+
 func (m *NetTestRunner) GetAgentConfigFileName() string {
 	return "net_config.json"
 }
 
+func (m *NetTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
 func (m *NetTestRunner) GetMeasuredMetrics() []string {
 	return []string{
 		"net_bytes_sent", "net_bytes_recv", "net_drop_in", "net_drop_out", "net_err_in",
 		"net_err_out", "net_packets_sent", "net_packets_recv"}
 }
 
-func (m *NetTestRunner) validateNetMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+// GetExpectedUnits returns the units of the metrics of agent_configs/net_config.json
+func (m *NetTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(m)
+}
 
+// validateNetMetrics fetches the values of every metric with a single GetMetricData request and checks the metrics
+// collected every 10 seconds are stored at high resolution with the expected units
+func (m *NetTestRunner) validateNetMetrics(metricNames []string) []status.TestResult {
 	dims, failed := m.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
 			Key:   "interface",
@@ -72,22 +77,14 @@ func (m *NetTestRunner) validateNetMetric(metricName string) status.TestResult {
 			Value: dimension.UnknownDimensionValue(),
 		},
 	})
-
 	if len(failed) > 0 {
-		return testResult
-	}
-
-	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
-
-	if err != nil {
-		return testResult
-	}
-
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
-		return testResult
+		return status.FailAll(metricNames, "failed to resolve dimensions %v", failed)
 	}
-
-	testResult.Status = status.SUCCESSFUL
-	return testResult
+	// the agent publishes the net counters as the delta over each collection interval
+	results := metric.ValidateSeries(namespace(), metricNames, dims, metric.AVERAGE, metric.HighResolutionStatPeriod,
+		func(string) []metric.SeriesAssertion {
+			return []metric.SeriesAssertion{metric.NonNegative()}
+		})
+	results = append(results, test_runner.ValidateHighResolution(m, dims)...)
+	return append(results, test_runner.ValidateUnits(m, dims)...)
 }
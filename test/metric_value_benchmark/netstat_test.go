@@ -8,6 +8,8 @@ package metric_value_benchmark
 import (
 	"log"
 
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
@@ -20,13 +22,28 @@ type NetStatTestRunner struct {
 
 var _ test_runner.ITestRunner = (*NetStatTestRunner)(nil)
 
+// netStatDimensions are the dimensions the metrics are validated with
+var netStatDimensions = []dimension.Instruction{
+	{
+		Key:   "InstanceId",
+		Value: dimension.UnknownDimensionValue(),
+	},
+}
+
 func (t *NetStatTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := t.GetMeasuredMetrics()
+	metricsToFetch, err := test_runner.MeasuredMetricsFromAgentConfig(t)
+	if err != nil {
+		return test_runner.AgentConfigFailure(t, err)
+	}
 	testResults := make([]status.TestResult, len(metricsToFetch))
 	for i, metricName := range metricsToFetch {
 		testResults[i] = t.validateNetStatMetric(metricName)
 	}
 
+	if dims, failed := t.DimensionFactory.GetDimensions(netStatDimensions); len(failed) == 0 {
+		testResults = append(testResults, test_runner.ValidateUnits(t, dims)...)
+	}
+
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
 		TestResults: testResults,
@@ -37,54 +54,51 @@ func (t *NetStatTestRunner) GetTestName() string {
 	return "NetStat"
 }
 
+func (t *NetStatTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("netstat")}
+}
+
 func (t *NetStatTestRunner) GetAgentConfigFileName() string {
 	return "netstat_config.json"
 }
 
+func (t *NetStatTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
+// GetMeasuredMetrics returns the measurements of agent_configs/netstat_config.json, none when it cannot be read (Validate
+// fails then)
 func (t *NetStatTestRunner) GetMeasuredMetrics() []string {
-	return []string{
-		"netstat_tcp_close",
-		"netstat_tcp_close_wait",
-		"netstat_tcp_closing",
-		"netstat_tcp_established",
-		"netstat_tcp_fin_wait1",
-		"netstat_tcp_fin_wait2",
-		"netstat_tcp_last_ack",
-		"netstat_tcp_listen",
-		"netstat_tcp_none",
-		"netstat_tcp_syn_sent",
-		"netstat_tcp_syn_recv",
-		"netstat_tcp_time_wait",
-		"netstat_udp_socket",
-	}
+	metricNames, _ := test_runner.MeasuredMetricsFromAgentConfig(t)
+	return metricNames
+}
+
+// GetExpectedUnits returns the units of the metrics of agent_configs/netstat_config.json
+func (t *NetStatTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(t)
 }
 
 func (t *NetStatTestRunner) validateNetStatMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
-	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
-		{
-			Key:   "InstanceId",
-			Value: dimension.UnknownDimensionValue(),
-		},
-	})
+	dims, failed := t.DimensionFactory.GetDimensions(netStatDimensions)
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
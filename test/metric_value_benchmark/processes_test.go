@@ -6,6 +6,8 @@
 package metric_value_benchmark
 
 import (
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
@@ -18,13 +20,28 @@ type ProcessesTestRunner struct {
 
 var _ test_runner.ITestRunner = (*ProcessesTestRunner)(nil)
 
+// processesDimensions are the dimensions the metrics are validated with
+var processesDimensions = []dimension.Instruction{
+	{
+		Key:   "InstanceId",
+		Value: dimension.UnknownDimensionValue(),
+	},
+}
+
 func (m *ProcessesTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := m.GetMeasuredMetrics()
+	metricsToFetch, err := test_runner.MeasuredMetricsFromAgentConfig(m)
+	if err != nil {
+		return test_runner.AgentConfigFailure(m, err)
+	}
 	testResults := make([]status.TestResult, len(metricsToFetch))
 	for i, name := range metricsToFetch {
 		testResults[i] = m.validateProcessesMetric(name)
 	}
 
+	if dims, failed := m.DimensionFactory.GetDimensions(processesDimensions); len(failed) == 0 {
+		testResults = append(testResults, test_runner.ValidateUnits(m, dims)...)
+	}
+
 	return status.TestGroupResult{
 		Name:        m.GetTestName(),
 		TestResults: testResults,
@@ -35,40 +52,49 @@ func (m *ProcessesTestRunner) GetTestName() string {
 	return "Processes"
 }
 
+func (m *ProcessesTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("processes")}
+}
+
 func (m *ProcessesTestRunner) GetAgentConfigFileName() string {
 	return "processes_config.json"
 }
 
+func (m *ProcessesTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
+// GetMeasuredMetrics returns the measurements of agent_configs/processes_config.json, none when it cannot be read (Validate
+// fails then)
 func (m *ProcessesTestRunner) GetMeasuredMetrics() []string {
-	return []string{
-		"processes_blocked", "processes_dead", "processes_idle", "processes_paging", "processes_running", "processes_sleeping", "processes_stopped",
-		"processes_total", "processes_total_threads", "processes_zombies"}
+	metricNames, _ := test_runner.MeasuredMetricsFromAgentConfig(m)
+	return metricNames
+}
+
+// GetExpectedUnits returns the units of the metrics of agent_configs/processes_config.json
+func (m *ProcessesTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(m)
 }
 
 func (m *ProcessesTestRunner) validateProcessesMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
-	dims, failed := m.DimensionFactory.GetDimensions([]dimension.Instruction{
-		{
-			Key:   "InstanceId",
-			Value: dimension.UnknownDimensionValue(),
-		},
-	})
+	dims, failed := m.DimensionFactory.GetDimensions(processesDimensions)
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
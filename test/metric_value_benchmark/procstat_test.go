@@ -6,6 +6,7 @@
 package metric_value_benchmark
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go-v2/aws"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
@@ -37,11 +38,15 @@ func (m *ProcStatTestRunner) GetTestName() string {
 	return "ProcStat"
 }
 
+func (m *ProcStatTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("procstat")}
+}
+
 func (m *ProcStatTestRunner) GetAgentConfigFileName() string {
 	return "procstat_config.json"
 }
 
-func (t *ProcStatTestRunner) SetupAfterAgentRun() error {
+func (t *ProcStatTestRunner) SetupAfterAgentRun(context.Context) error {
 	return nil
 }
 
@@ -52,10 +57,7 @@ func (m *ProcStatTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (m *ProcStatTestRunner) validateProcStatMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
 	dims, failed := m.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -73,16 +75,19 @@ func (m *ProcStatTestRunner) validateProcStatMetric(metricName string) status.Te
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
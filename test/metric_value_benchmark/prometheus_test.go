@@ -28,6 +28,9 @@ var _ test_runner.ITestRunner = (*PrometheusTestRunner)(nil)
 //go:embed agent_configs/prometheus.yaml
 var prometheusConfig string
 
+// prometheusLogGroup is the log_group_name of prometheus_config.json before the test run renders it
+const prometheusLogGroup = "prometheus_test"
+
 const prometheusMetrics = `prometheus_test_untyped{include="yes",prom_type="untyped"} 1
 # TYPE prometheus_test_counter counter
 prometheus_test_counter{include="yes",prom_type="counter"} 1
@@ -68,6 +71,10 @@ func (t *PrometheusTestRunner) GetTestName() string {
 	return "Prometheus"
 }
 
+func (t *PrometheusTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("prometheus"), test_runner.TagNeedsRoot}
+}
+
 func (t *PrometheusTestRunner) GetAgentConfigFileName() string {
 	return "prometheus_config.json"
 }
@@ -83,11 +90,13 @@ func (t *PrometheusTestRunner) SetupBeforeAgentRun() error {
 		"sudo python3 -m http.server 8101 --directory /tmp &> /dev/null &",
 	}
 
-	err = common.RunCommands(startPrometheusCommands)
-	if err != nil {
-		return err
-	}
-	return nil
+	// registered first so a partial setup is undone as well, pkill exits with 1 when the server did not start
+	t.Resources.RegisterCommands("prometheus endpoint", []string{
+		"sudo pkill -f 'http.server 8101'; [ $? -le 1 ]",
+		"sudo rm -f /tmp/prometheus_config.yaml /tmp/metrics",
+	})
+	t.Resources.RegisterLogGroup(common.RunLogGroup(prometheusLogGroup))
+	return common.RunCommands(startPrometheusCommands)
 }
 
 func (t *PrometheusTestRunner) GetMeasuredMetrics() []string {
@@ -101,10 +110,7 @@ func (t *PrometheusTestRunner) GetMeasuredMetrics() []string {
 }
 
 func (t *PrometheusTestRunner) validatePrometheusMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
 	var dims []types.Dimension
 	var failed []dimension.Instruction
@@ -154,16 +160,19 @@ func (t *PrometheusTestRunner) validatePrometheusMetric(metricName string) statu
 	}
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -6,6 +6,7 @@
 package metric_value_benchmark
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -20,8 +21,6 @@ const (
 	send_interval = 10 * time.Millisecond
 )
 
-var done = make(chan bool)
-
 var _ test_runner.ITestRunner = (*StatsdTestRunner)(nil)
 
 type StatsdTestRunner struct {
@@ -29,13 +28,12 @@ type StatsdTestRunner struct {
 }
 
 func (t *StatsdTestRunner) Validate() status.TestGroupResult {
-	// Stop sender.
-	close(done)
 	metricsToFetch := t.GetMeasuredMetrics()
 	results := make([]status.TestResult, len(metricsToFetch))
 	for i, metricName := range metricsToFetch {
-		results[i] = metric.ValidateStatsdMetric(t.DimensionFactory, namespace, "InstanceId", metricName, metric.StatsdMetricValues[i], t.GetAgentRunDuration(), send_interval)
+		results[i] = metric.ValidateStatsdMetric(t.DimensionFactory, namespace(), "InstanceId", metricName, metric.StatsdMetricValues[i], t.GetAgentRunDuration(), send_interval)
 	}
+	results = append(results, metric.ValidateStatsdHighResolution(t.DimensionFactory, namespace(), "InstanceId", metricsToFetch)...)
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
 		TestResults: results,
@@ -46,6 +44,10 @@ func (t *StatsdTestRunner) GetTestName() string {
 	return "EC2StatsD"
 }
 
+func (t *StatsdTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("statsd")}
+}
+
 func (t *StatsdTestRunner) GetAgentConfigFileName() string {
 	return "statsd_config.json"
 }
@@ -54,14 +56,14 @@ func (t *StatsdTestRunner) GetAgentRunDuration() time.Duration {
 	return 3 * time.Minute
 }
 
-func (t *StatsdTestRunner) SetupAfterAgentRun() error {
-	// Send each metric once a second.
-	go t.sender()
+func (t *StatsdTestRunner) SetupAfterAgentRun(ctx context.Context) error {
+	// Send each metric once a second until the agent run ends.
+	go t.sender(ctx)
 	return nil
 }
 
 // sender will send statsd metric values with the specified names and values.
-func (t *StatsdTestRunner) sender() {
+func (t *StatsdTestRunner) sender(ctx context.Context) {
 	client, _ := statsd.New(
 		"127.0.0.1:8125",
 		statsd.WithMaxMessagesPerPayload(1),
@@ -72,7 +74,7 @@ func (t *StatsdTestRunner) sender() {
 	tags := []string{"key:value"}
 	for {
 		select {
-		case <-done:
+		case <-ctx.Done():
 			return
 		case <-ticker.C:
 			for i, name := range metric.StatsdMetricNames {
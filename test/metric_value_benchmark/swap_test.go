@@ -8,6 +8,8 @@ package metric_value_benchmark
 import (
 	"log"
 
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
@@ -20,13 +22,28 @@ type SwapTestRunner struct {
 
 var _ test_runner.ITestRunner = (*SwapTestRunner)(nil)
 
+// swapDimensions are the dimensions the metrics are validated with
+var swapDimensions = []dimension.Instruction{
+	{
+		Key:   "InstanceId",
+		Value: dimension.UnknownDimensionValue(),
+	},
+}
+
 func (t *SwapTestRunner) Validate() status.TestGroupResult {
-	metricsToFetch := t.GetMeasuredMetrics()
+	metricsToFetch, err := test_runner.MeasuredMetricsFromAgentConfig(t)
+	if err != nil {
+		return test_runner.AgentConfigFailure(t, err)
+	}
 	testResults := make([]status.TestResult, len(metricsToFetch))
 	for i, metricName := range metricsToFetch {
 		testResults[i] = t.validateSwapMetric(metricName)
 	}
 
+	if dims, failed := t.DimensionFactory.GetDimensions(swapDimensions); len(failed) == 0 {
+		testResults = append(testResults, test_runner.ValidateUnits(t, dims)...)
+	}
+
 	return status.TestGroupResult{
 		Name:        t.GetTestName(),
 		TestResults: testResults,
@@ -37,43 +54,50 @@ func (t *SwapTestRunner) GetTestName() string {
 	return "Swap"
 }
 
+func (t *SwapTestRunner) GetTags() []string {
+	return []string{test_runner.PluginTag("swap")}
+}
+
 func (t *SwapTestRunner) GetAgentConfigFileName() string {
 	return "swap_config.json"
 }
 
+func (t *SwapTestRunner) CanShareAgentRun() bool {
+	return true
+}
+
+// GetMeasuredMetrics returns the measurements of agent_configs/swap_config.json, none when it cannot be read (Validate
+// fails then)
 func (t *SwapTestRunner) GetMeasuredMetrics() []string {
-	return []string{
-		"swap_free",
-		"swap_used",
-		"swap_used_percent",
-	}
+	metricNames, _ := test_runner.MeasuredMetricsFromAgentConfig(t)
+	return metricNames
+}
+
+// GetExpectedUnits returns the units of the metrics of agent_configs/swap_config.json
+func (t *SwapTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return test_runner.ExpectedUnits(t)
 }
 
 func (t *SwapTestRunner) validateSwapMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(namespace(), metricName)
 
-	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
-		{
-			Key:   "InstanceId",
-			Value: dimension.UnknownDimensionValue(),
-		},
-	})
+	dims, failed := t.DimensionFactory.GetDimensions(swapDimensions)
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(namespace(), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -0,0 +1,60 @@
+#!/bin/bash
+# Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+# SPDX-License-Identifier: MIT
+
+# Installs Fluent Bit and starts it with the fluent-bit.conf given as the first argument, defaulting to the one
+# next to this script. fluent-bit.conf reads the INSTANCE_ID and AWS_REGION environment variables.
+set -euo pipefail
+
+config=${1:-$(dirname "$0")/fluent-bit.conf}
+: "${INSTANCE_ID:?INSTANCE_ID must be exported}"
+: "${AWS_REGION:?AWS_REGION must be exported}"
+
+# The Fluent Bit release the performance results are measured with
+fluent_bit_version=3.0.7
+# Fingerprint of the key signing the Fluent Bit packages (https://docs.fluentbit.io/manual/installation/linux)
+fluent_bit_key_fingerprint=C3C0A28534B9293EAF51FABD9F9DDC083888C1CD
+
+install_fluent_bit() {
+  local key release
+  key=$(mktemp)
+  curl -sSf -o "$key" https://packages.fluentbit.io/fluentbit.key
+  if ! gpg --quiet --with-colons --with-fingerprint "$key" 2> /dev/null | grep -q "^fpr:*${fluent_bit_key_fingerprint}:"; then
+    echo "The Fluent Bit package key does not have the fingerprint ${fluent_bit_key_fingerprint}"
+    exit 1
+  fi
+  sudo rpm --import "$key"
+  rm -f "$key"
+
+  # Amazon Linux 2 or 2023, yum checks the packages are signed with the imported key
+  release=$(. /etc/os-release && echo "$VERSION_ID")
+  sudo tee /etc/yum.repos.d/fluent-bit.repo > /dev/null << REPO
+[fluent-bit]
+name=Fluent Bit
+baseurl=https://packages.fluentbit.io/amazonlinux/${release}/
+gpgcheck=1
+gpgkey=https://packages.fluentbit.io/fluentbit.key
+enabled=1
+REPO
+  sudo yum install -y "fluent-bit-${fluent_bit_version}"
+}
+
+if ! rpm -q "fluent-bit-${fluent_bit_version}" > /dev/null 2>&1; then
+  install_fluent_bit
+fi
+
+sudo cp "$config" /etc/fluent-bit-test.conf
+sudo INSTANCE_ID="$INSTANCE_ID" AWS_REGION="$AWS_REGION" \
+  nohup /opt/fluent-bit/bin/fluent-bit -c /etc/fluent-bit-test.conf > /tmp/fluent-bit.log 2>&1 < /dev/null &
+
+# The validator sends the load to the forward input as soon as it starts
+for _ in $(seq 1 30); do
+  if (echo > /dev/tcp/127.0.0.1/24224) 2> /dev/null; then
+    echo "Fluent Bit is listening on 127.0.0.1:24224"
+    exit 0
+  fi
+  sleep 1
+done
+echo "Fluent Bit did not start listening on 127.0.0.1:24224"
+cat /tmp/fluent-bit.log
+exit 1
@@ -45,25 +45,25 @@ func (t ProxyTestRunner) Validate() status.TestGroupResult {
 }
 
 func (t *ProxyTestRunner) validateMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace(namespace), metricName)
 
 	dims := getDimensions(environment.GetEnvironmentMetaData().InstanceId)
 	if len(dims) == 0 {
+		testResult.Fail("no dimensions")
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace(namespace), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -99,7 +99,9 @@ func TestProxy(t *testing.T) {
 		test_runner.BaseTestRunner{},
 		env.ProxyUrl,
 	}}
-	result := runner.Run()
+	ctx, cancel := test_runner.Context(t)
+	defer cancel()
+	result := runner.Run(ctx)
 	if result.GetStatus() != status.SUCCESSFUL {
 		t.Fatal("Proxy test failed")
 		result.Print()
@@ -21,7 +21,8 @@ import (
 )
 
 const (
-	namespace = "SSLCertTest"
+	namespace        = "SSLCertTest"
+	commonConfigPath = "/opt/aws/amazon-cloudwatch-agent/etc/common-config.toml"
 )
 
 func init() {
@@ -48,10 +49,7 @@ func (t SslCertTestRunner) Validate() status.TestGroupResult {
 }
 
 func (t *SslCertTestRunner) validateMetric(metricName string) status.TestResult {
-	testResult := status.TestResult{
-		Name:   metricName,
-		Status: status.FAILED,
-	}
+	testResult := metric.NewTestResult(common.RunNamespace(namespace), metricName)
 
 	dims, failed := t.DimensionFactory.GetDimensions([]dimension.Instruction{
 		{
@@ -65,18 +63,21 @@ func (t *SslCertTestRunner) validateMetric(metricName string) status.TestResult
 	})
 
 	if len(failed) > 0 {
+		testResult.Fail("failed to resolve dimensions %v", failed)
 		return testResult
 	}
+	metric.AddDimensions(&testResult, dims)
 
 	fetcher := metric.MetricValueFetcher{}
-	values, err := fetcher.Fetch(namespace, metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
+	values, err := fetcher.Fetch(common.RunNamespace(namespace), metricName, dims, metric.AVERAGE, metric.HighResolutionStatPeriod)
 
 	log.Printf("metric values are %v", values)
 	if err != nil {
+		testResult.Fail("failed to fetch metric values: %v", err)
 		return testResult
 	}
 
-	if !metric.IsAllValuesGreaterThanOrEqualToExpectedValue(metricName, values, 0) {
+	if !metric.ValidateValues(&testResult, metricName, values, 0) {
 		return testResult
 	}
 
@@ -98,11 +99,18 @@ func (t SslCertTestRunner) GetMeasuredMetrics() []string {
 
 func (t *SslCertTestRunner) SetupBeforeAgentRun() error {
 	backupCertPath := t.caCertPath + ".bak"
+	backupCommonConfigPath := commonConfigPath + ".bak"
 	commands := []string{
+		fmt.Sprintf("sudo cp -p %s %s", commonConfigPath, backupCommonConfigPath),
 		fmt.Sprintf("sudo mv %s %s", t.caCertPath, backupCertPath),
-		"echo [ssl] | sudo tee -a /opt/aws/amazon-cloudwatch-agent/etc/common-config.toml",
-		"echo ca_bundle_path = \\\"" + backupCertPath + "\\\" | sudo tee -a /opt/aws/amazon-cloudwatch-agent/etc/common-config.toml",
+		"echo [ssl] | sudo tee -a " + commonConfigPath,
+		"echo ca_bundle_path = \\\"" + backupCertPath + "\\\" | sudo tee -a " + commonConfigPath,
 	}
+	// registered first so a partial setup is undone as well, the common config is restored as it was
+	t.Resources.RegisterCommands("ca bundle", []string{
+		fmt.Sprintf("[ ! -f %s ] || sudo mv %s %s", backupCommonConfigPath, backupCommonConfigPath, commonConfigPath),
+		fmt.Sprintf("[ ! -f %s ] || sudo mv %s %s", backupCertPath, backupCertPath, t.caCertPath),
+	})
 	err := common.RunCommands(commands)
 	if err != nil {
 		return err
@@ -119,7 +127,9 @@ func TestSSLCert(t *testing.T) {
 		test_runner.BaseTestRunner{DimensionFactory: factory},
 		env.CaCertPath,
 	}}
-	result := runner.Run()
+	ctx, cancel := test_runner.Context(t)
+	defer cancel()
+	result := runner.Run(ctx)
 	if result.GetStatus() != status.SUCCESSFUL {
 		t.Fatal("SSL Cert test failed")
 		result.Print()
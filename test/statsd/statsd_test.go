@@ -32,6 +32,7 @@ func (suite *StatsDTestSuite) SetupSuite() {
 
 func (suite *StatsDTestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports("StatsDTestSuite")
 	fmt.Println(">>>> Finished StatsDTestSuite")
 }
 
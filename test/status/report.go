@@ -0,0 +1,280 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package status
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultSuiteName = "TestSuite"
+
+// JUnit XML format understood by most CI systems
+// https://github.com/testmoapp/junitxml
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Time       float64          `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Time       float64          `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       float64          `xml:"time,attr"`
+	Failure    *junitFailure    `xml:"failure,omitempty"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitXML returns the suite result with a testsuite per test group and a testcase per test result
+func (r TestSuiteResult) JUnitXML() ([]byte, error) {
+	suites := junitTestSuites{Name: r.name()}
+	for _, group := range r.TestGroupResults {
+		suite := junitTestSuite{
+			Name:  group.Name,
+			Tests: len(group.TestResults),
+			Time:  group.Duration.Seconds(),
+		}
+		if group.Diagnostics != "" {
+			suite.Properties = &junitProperties{Properties: []junitProperty{{Name: "diagnostics", Value: group.Diagnostics}}}
+		}
+		for _, result := range group.TestResults {
+			testCase := junitTestCase{
+				Name:      result.Name,
+				ClassName: group.Name,
+				Time:      result.Duration.Seconds(),
+			}
+			if result.Status == FAILED {
+				suite.Failures++
+				testCase.Failure = &junitFailure{Message: result.failureMessage(), Content: result.details()}
+			}
+			if properties := result.properties(); len(properties) > 0 {
+				testCase.Properties = &junitProperties{Properties: properties}
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Time += suite.Time
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	output, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
+
+type jsonTestSuite struct {
+	Name        string          `json:"name"`
+	Status      TestStatus      `json:"status"`
+	TestGroups  []jsonTestGroup `json:"test_groups"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+type jsonTestGroup struct {
+	Name            string           `json:"name"`
+	Status          TestStatus       `json:"status"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Diagnostics     string           `json:"diagnostics,omitempty"`
+	TestResults     []jsonTestResult `json:"test_results"`
+}
+
+type jsonTestResult struct {
+	Name            string            `json:"name"`
+	Status          TestStatus        `json:"status"`
+	DurationSeconds float64           `json:"duration_seconds"`
+	Reason          string            `json:"reason,omitempty"`
+	Identifiers     map[string]string `json:"identifiers,omitempty"`
+	Expected        string            `json:"expected,omitempty"`
+	Observed        string            `json:"observed,omitempty"`
+}
+
+// JSON returns the suite result with every test group and test result so the results can be tracked over time
+func (r TestSuiteResult) JSON() ([]byte, error) {
+	suite := jsonTestSuite{
+		Name:        r.name(),
+		Status:      r.GetStatus(),
+		TestGroups:  []jsonTestGroup{},
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, group := range r.TestGroupResults {
+		jsonGroup := jsonTestGroup{
+			Name:            group.Name,
+			Status:          group.GetStatus(),
+			DurationSeconds: group.Duration.Seconds(),
+			Diagnostics:     group.Diagnostics,
+			TestResults:     []jsonTestResult{},
+		}
+		for _, result := range group.TestResults {
+			jsonGroup.TestResults = append(jsonGroup.TestResults, jsonTestResult{
+				Name:            result.Name,
+				Status:          result.Status,
+				DurationSeconds: result.Duration.Seconds(),
+				Reason:          result.Reason,
+				Identifiers:     result.Identifiers,
+				Expected:        result.Expected,
+				Observed:        result.Observed,
+			})
+		}
+		suite.TestGroups = append(suite.TestGroups, jsonGroup)
+	}
+	return json.MarshalIndent(suite, "", "  ")
+}
+
+// Markdown returns a summary table of the test groups followed by the details of every failed test result
+func (r TestSuiteResult) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s: %s\n\n", r.name(), r.GetStatus())
+	sb.WriteString("| Test Group | Status | Passed | Failed | Duration |\n")
+	sb.WriteString("|------------|--------|--------|--------|----------|\n")
+	var failed, diagnostics []string
+	for _, group := range r.TestGroupResults {
+		if group.Diagnostics != "" {
+			diagnostics = append(diagnostics, fmt.Sprintf("- %s: %s", escapeMarkdown(group.Name), group.Diagnostics))
+		}
+		var passedCount, failedCount int
+		for _, result := range group.TestResults {
+			if result.Status == FAILED {
+				failedCount++
+				failed = append(failed, fmt.Sprintf("| %s | %s | %s | %s | %s |",
+					escapeMarkdown(group.Name), escapeMarkdown(result.Name), escapeMarkdown(result.failureMessage()),
+					escapeMarkdown(result.Expected), escapeMarkdown(result.Observed)))
+			} else {
+				passedCount++
+			}
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %d | %d | %v |\n", escapeMarkdown(group.Name), group.GetStatus(), passedCount, failedCount, group.Duration.Round(time.Second))
+	}
+
+	if len(failed) > 0 {
+		sb.WriteString("\n### Failures\n\n")
+		sb.WriteString("| Test Group | Test | Reason | Expected | Observed |\n")
+		sb.WriteString("|------------|------|--------|----------|----------|\n")
+		for _, line := range failed {
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		sb.WriteString("\n### Diagnostics\n\n")
+		for _, line := range diagnostics {
+			sb.WriteString(line + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// WriteReports writes the JUnit XML, JSON and Markdown reports of the suite result to the directory
+// (e.g StatsDTestSuite.xml, StatsDTestSuite.json and StatsDTestSuite.md). Nothing is written when the
+// directory is empty.
+func (r TestSuiteResult) WriteReports(directory string) error {
+	if directory == "" {
+		return nil
+	}
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	junitReport, err := r.JUnitXML()
+	if err != nil {
+		return err
+	}
+	jsonReport, err := r.JSON()
+	if err != nil {
+		return err
+	}
+
+	reports := map[string][]byte{
+		".xml":  junitReport,
+		".json": jsonReport,
+		".md":   []byte(r.Markdown()),
+	}
+	for extension, report := range reports {
+		if err = os.WriteFile(filepath.Join(directory, r.name()+extension), report, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r TestSuiteResult) name() string {
+	if r.Name == "" {
+		return defaultSuiteName
+	}
+	return r.Name
+}
+
+func (r TestResult) failureMessage() string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	return string(FAILED)
+}
+
+// details describes the identifiers and the expected and observed values of a failed result
+func (r TestResult) details() string {
+	var lines []string
+	for _, property := range r.properties() {
+		lines = append(lines, fmt.Sprintf("%s: %s", property.Name, property.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r TestResult) properties() []junitProperty {
+	var properties []junitProperty
+	keys := make([]string, 0, len(r.Identifiers))
+	for key := range r.Identifiers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		properties = append(properties, junitProperty{Name: key, Value: r.Identifiers[key]})
+	}
+	if r.Expected != "" {
+		properties = append(properties, junitProperty{Name: "expected", Value: r.Expected})
+	}
+	if r.Observed != "" {
+		properties = append(properties, junitProperty{Name: "observed", Value: r.Observed})
+	}
+	return properties
+}
+
+func escapeMarkdown(value string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(value)
+}
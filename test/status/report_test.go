@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package status
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSuiteResult() TestSuiteResult {
+	failed := TestResult{
+		Name:     "mem_used|percent",
+		Duration: 2 * time.Second,
+		Expected: ">= 0",
+		Observed: "[-1]",
+	}
+	failed.AddIdentifier("namespace", "CWAgent")
+	failed.AddIdentifier("metric_name", "mem_used_percent")
+	failed.Fail("values out of the expected range")
+	return TestSuiteResult{
+		Name: "MetricValueBenchmarkTestSuite",
+		TestGroupResults: []TestGroupResult{
+			{
+				Name:        "MemTestRunner",
+				Duration:    time.Minute,
+				Diagnostics: "s3://bucket/diagnostics.tar.gz",
+				TestResults: []TestResult{
+					{Name: "mem_total", Status: SUCCESSFUL, Duration: time.Second},
+					failed,
+				},
+			},
+			{
+				Name:        "CPUTestRunner",
+				Duration:    30 * time.Second,
+				TestResults: []TestResult{{Name: "cpu_time_active", Status: SUCCESSFUL}},
+			},
+		},
+	}
+}
+
+func TestJUnitXML(t *testing.T) {
+	report, err := testSuiteResult().JUnitXML()
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(report, &suites))
+	assert.Equal(t, "MetricValueBenchmarkTestSuite", suites.Name)
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 90.0, suites.Time)
+	require.Len(t, suites.TestSuites, 2)
+
+	mem := suites.TestSuites[0]
+	assert.Equal(t, "MemTestRunner", mem.Name)
+	assert.Equal(t, 2, mem.Tests)
+	assert.Equal(t, 1, mem.Failures)
+	require.NotNil(t, mem.Properties)
+	assert.Equal(t, []junitProperty{{Name: "diagnostics", Value: "s3://bucket/diagnostics.tar.gz"}}, mem.Properties.Properties)
+	require.Len(t, mem.TestCases, 2)
+	assert.Nil(t, mem.TestCases[0].Failure)
+	assert.Nil(t, mem.TestCases[0].Properties)
+
+	failed := mem.TestCases[1]
+	assert.Equal(t, "MemTestRunner", failed.ClassName)
+	assert.Equal(t, 2.0, failed.Time)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, "values out of the expected range", failed.Failure.Message)
+	assert.Equal(t, "metric_name: mem_used_percent\nnamespace: CWAgent\nexpected: >= 0\nobserved: [-1]", failed.Failure.Content)
+	require.NotNil(t, failed.Properties)
+	assert.Equal(t, []junitProperty{
+		{Name: "metric_name", Value: "mem_used_percent"},
+		{Name: "namespace", Value: "CWAgent"},
+		{Name: "expected", Value: ">= 0"},
+		{Name: "observed", Value: "[-1]"},
+	}, failed.Properties.Properties)
+
+	assert.Nil(t, suites.TestSuites[1].Properties)
+	assert.Equal(t, 0, suites.TestSuites[1].Failures)
+}
+
+func TestJSON(t *testing.T) {
+	report, err := testSuiteResult().JSON()
+	require.NoError(t, err)
+
+	var suite jsonTestSuite
+	require.NoError(t, json.Unmarshal(report, &suite))
+	assert.Equal(t, "MetricValueBenchmarkTestSuite", suite.Name)
+	assert.Equal(t, FAILED, suite.Status)
+	assert.False(t, suite.GeneratedAt.IsZero())
+	require.Len(t, suite.TestGroups, 2)
+	assert.Equal(t, FAILED, suite.TestGroups[0].Status)
+	assert.Equal(t, 60.0, suite.TestGroups[0].DurationSeconds)
+	assert.Equal(t, "s3://bucket/diagnostics.tar.gz", suite.TestGroups[0].Diagnostics)
+	assert.Equal(t, SUCCESSFUL, suite.TestGroups[1].Status)
+	assert.Equal(t, []jsonTestResult{
+		{Name: "mem_total", Status: SUCCESSFUL, DurationSeconds: 1},
+		{
+			Name:            "mem_used|percent",
+			Status:          FAILED,
+			DurationSeconds: 2,
+			Reason:          "values out of the expected range",
+			Identifiers:     map[string]string{"namespace": "CWAgent", "metric_name": "mem_used_percent"},
+			Expected:        ">= 0",
+			Observed:        "[-1]",
+		},
+	}, suite.TestGroups[0].TestResults)
+
+	// the optional fields of a successful result are left out
+	var raw struct {
+		TestGroups []struct {
+			TestResults []map[string]interface{} `json:"test_results"`
+		} `json:"test_groups"`
+	}
+	require.NoError(t, json.Unmarshal(report, &raw))
+	assert.Equal(t, map[string]interface{}{"name": "mem_total", "status": "Successful", "duration_seconds": 1.0}, raw.TestGroups[0].TestResults[0])
+}
+
+func TestMarkdown(t *testing.T) {
+	expected := "## MetricValueBenchmarkTestSuite: Failed\n\n" +
+		"| Test Group | Status | Passed | Failed | Duration |\n" +
+		"|------------|--------|--------|--------|----------|\n" +
+		"| MemTestRunner | Failed | 1 | 1 | 1m0s |\n" +
+		"| CPUTestRunner | Successful | 1 | 0 | 30s |\n" +
+		"\n### Failures\n\n" +
+		"| Test Group | Test | Reason | Expected | Observed |\n" +
+		"|------------|------|--------|----------|----------|\n" +
+		"| MemTestRunner | mem_used\\|percent | values out of the expected range | >= 0 | [-1] |\n" +
+		"\n### Diagnostics\n\n" +
+		"- MemTestRunner: s3://bucket/diagnostics.tar.gz\n"
+	assert.Equal(t, expected, testSuiteResult().Markdown())
+}
+
+func TestWriteReports(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "reports")
+	require.NoError(t, testSuiteResult().WriteReports(directory))
+	for _, extension := range []string{".xml", ".json", ".md"} {
+		_, err := os.Stat(filepath.Join(directory, "MetricValueBenchmarkTestSuite"+extension))
+		assert.NoError(t, err)
+	}
+
+	// nothing is written without a directory
+	assert.NoError(t, TestSuiteResult{}.WriteReports(""))
+}
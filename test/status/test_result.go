@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"text/tabwriter"
+	"time"
 )
 
 type TestSuiteResult struct {
@@ -37,6 +38,9 @@ func (r TestSuiteResult) Print() {
 type TestGroupResult struct {
 	Name        string
 	TestResults []TestResult
+	Duration    time.Duration
+	// Diagnostics is the location of the diagnostics bundle collected when the test group failed
+	Diagnostics string
 }
 
 func (r TestGroupResult) GetStatus() TestStatus {
@@ -53,13 +57,48 @@ func (r TestGroupResult) Print() {
 	log.Printf("==============%v==============", string(r.GetStatus()))
 	w := tabwriter.NewWriter(log.Writer(), 1, 1, 1, ' ', 0)
 	for _, result := range r.TestResults {
-		fmt.Fprintln(w, result.Name, "\t", result.Status, "\t")
+		fmt.Fprintln(w, result.Name, "\t", result.Status, "\t", result.Reason, "\t")
 	}
 	w.Flush()
+	if r.Diagnostics != "" {
+		log.Printf("Diagnostics: %s", r.Diagnostics)
+	}
 	log.Printf("==============================")
 }
 
 type TestResult struct {
-	Name   string
-	Status TestStatus
+	Name     string
+	Status   TestStatus
+	Duration time.Duration
+	// Reason explains why the check failed
+	Reason string
+	// Identifiers are the metric or log identifiers the check looked at (e.g namespace, metric name, dimensions, log group)
+	Identifiers map[string]string
+	// Expected and Observed are the values the check compared
+	Expected string
+	Observed string
+}
+
+// Fail marks the result as failed with the reason
+func (r *TestResult) Fail(format string, args ...interface{}) {
+	r.Status = FAILED
+	r.Reason = fmt.Sprintf(format, args...)
+}
+
+// FailAll returns a failed result with the reason per name, for the checks that could not run
+func FailAll(names []string, format string, args ...interface{}) []TestResult {
+	results := make([]TestResult, len(names))
+	for i, name := range names {
+		results[i] = TestResult{Name: name}
+		results[i].Fail(format, args...)
+	}
+	return results
+}
+
+// AddIdentifier records a metric or log identifier the check looked at
+func (r *TestResult) AddIdentifier(key, value string) {
+	if r.Identifiers == nil {
+		r.Identifiers = map[string]string{}
+	}
+	r.Identifiers[key] = value
 }
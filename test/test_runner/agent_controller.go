@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/agentcontrollertype"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const localConfigFileName = "cwagent-test-config.json"
+
+// agentController returns the agent controller of the test runner or the one selected with the agentController flag
+func (t *TestRunner) agentController() common.AgentController {
+	if t.AgentController == nil {
+		t.AgentController = common.NewAgentController(environment.GetEnvironmentMetaData())
+	}
+	return t.AgentController
+}
+
+// agentConfigOutputPath is where the setup copies the agent config to. The agents started without the package
+// read it from a temporary file so the package directory does not have to exist.
+func agentConfigOutputPath() string {
+	if environment.GetEnvironmentMetaData().AgentController == agentcontrollertype.CTL {
+		return configOutputPath
+	}
+	return filepath.Join(os.TempDir(), localConfigFileName)
+}
+
+// agentConfigLocation is the config path passed to AgentController.Start
+func agentConfigLocation(testRunner ITestRunner) string {
+	if testRunner.UseSSM() {
+		return common.SSMConfigPrefix + testRunner.SSMParameterName()
+	}
+	return agentConfigOutputPath()
+}
@@ -6,13 +6,16 @@
 package test_runner
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
+	"runtime/debug"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
@@ -31,21 +34,34 @@ type ITestRunner interface {
 	GetAgentConfigFileName() string
 	GetAgentRunDuration() time.Duration
 	GetMeasuredMetrics() []string
+	// GetExpectedUnits returns the unit of each measured metric whose data points Validate checks to all have the unit
+	// (see ValidateUnits)
+	GetExpectedUnits() (map[string]types.StandardUnit, error)
 	SetupBeforeAgentRun() error
-	SetupAfterAgentRun() error
+	// SetupAfterAgentRun starts the load for the agent (e.g sending statsd metrics). The context is done once the
+	// agent run ends, so load generators running in the background stop with the agent.
+	SetupAfterAgentRun(ctx context.Context) error
 	UseSSM() bool
 	SSMParameterName() string
 	SetUpConfig() error
 	SetAgentConfig(config AgentConfig)
+	CanShareAgentRun() bool
+	// GetTags returns the tags the test selector can select the test runner with (e.g plugin:cpu or needs-root)
+	GetTags() []string
+	Cleanup() error
 }
 
 type TestRunner struct {
 	TestRunner ITestRunner
+	// AgentController is optional and defaults to the one selected with the agentController flag
+	AgentController common.AgentController
 }
 
 type BaseTestRunner struct {
 	DimensionFactory dimension.Factory
 	AgentConfig      AgentConfig
+	// Resources created by the setup which are released by Cleanup
+	Resources ResourceRegistry
 }
 
 type AgentConfig struct {
@@ -58,19 +74,26 @@ func (t *BaseTestRunner) SetupBeforeAgentRun() error {
 	return t.SetUpConfig()
 }
 
+// SetUpConfig renders the agent config template (see common.AgentConfigVariables) to where the agent reads it from
 func (t *BaseTestRunner) SetUpConfig() error {
 	agentConfigPath := filepath.Join(agentConfigDirectory, t.AgentConfig.ConfigFileName)
 	log.Printf("Starting agent using agent config file %s", agentConfigPath)
-	common.CopyFile(agentConfigPath, configOutputPath)
+	variables := common.NewAgentConfigVariables(environment.GetEnvironmentMetaData())
+	outputPath := agentConfigOutputPath()
+	t.Resources.RegisterFile(outputPath)
+	if err := common.CopyAgentConfig(agentConfigPath, outputPath, variables); err != nil {
+		return err
+	}
 	if t.AgentConfig.UseSSM {
 		log.Printf("Starting agent from ssm parameter %s", agentConfigPath)
-		agentConfigByteArray, err := os.ReadFile(agentConfigPath)
+		agentConfigByteArray, err := common.RenderAgentConfig(agentConfigPath, variables)
 		if err != nil {
-			return errors.New("failed while reading config file")
+			return fmt.Errorf("failed while reading config file: %w", err)
 		}
 		agentConfig := string(agentConfigByteArray)
 		if agentConfig != awsservice.GetStringParameter(t.AgentConfig.SSMParameterName) {
 			log.Printf("ssm agent config %s canged upload new config", t.AgentConfig.SSMParameterName)
+			t.Resources.RegisterSSMParameter(t.AgentConfig.SSMParameterName)
 			err = awsservice.PutStringParameter(t.AgentConfig.SSMParameterName, agentConfig)
 			if err != nil {
 				return fmt.Errorf("failed to upload ssm parameter err %v", err)
@@ -80,10 +103,15 @@ func (t *BaseTestRunner) SetUpConfig() error {
 	return nil
 }
 
-func (t *BaseTestRunner) SetupAfterAgentRun() error {
+func (t *BaseTestRunner) SetupAfterAgentRun(context.Context) error {
 	return nil
 }
 
+// Cleanup releases the resources registered by the setup. Test runners overriding it should still call it.
+func (t *BaseTestRunner) Cleanup() error {
+	return t.Resources.Release()
+}
+
 func (t *BaseTestRunner) GetAgentRunDuration() time.Duration {
 	return 30 * time.Second
 }
@@ -100,10 +128,51 @@ func (t *BaseTestRunner) SetAgentConfig(agentConfig AgentConfig) {
 	t.AgentConfig = agentConfig
 }
 
-func (t *TestRunner) Run() status.TestGroupResult {
+// CanShareAgentRun is false by default so the test runner gets an agent run of its own.
+// Test runners only reading host metrics can share an agent run with a merged agent config (see GroupTestRunners).
+func (t *BaseTestRunner) CanShareAgentRun() bool {
+	return false
+}
+
+// GetExpectedUnits returns no units by default, the units of the metrics are not checked
+func (t *BaseTestRunner) GetExpectedUnits() (map[string]types.StandardUnit, error) {
+	return nil, nil
+}
+
+// GetTags returns no tags by default, the test runner is still tagged with its OS, architecture, name and speed
+func (t *BaseTestRunner) GetTags() []string {
+	return nil
+}
+
+// Run starts the agent, validates the test runner and always cleans up its resources afterwards. A panic in the
+// test runner fails the test group instead of skipping the cleanup, and the agent run stops early when the context
+// is done (see Context).
+func (t *TestRunner) Run(ctx context.Context) (testGroupResult status.TestGroupResult) {
 	testName := t.TestRunner.GetTestName()
 	log.Printf("Running %v", testName)
-	testGroupResult, err := t.RunAgent()
+	startTime := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%v test group panicked: %v\n%s", testName, r, debug.Stack())
+			if err := t.agentController().Stop(); err != nil {
+				log.Printf("Failed to stop agent after %v test group panicked: %v", testName, err)
+			}
+			testGroupResult = status.TestGroupResult{
+				Name: testName,
+				TestResults: []status.TestResult{
+					{Name: testName, Status: status.FAILED, Reason: fmt.Sprintf("panic: %v", r)},
+				},
+			}
+		}
+		if testGroupResult.GetStatus() != status.SUCCESSFUL {
+			testGroupResult.Diagnostics = collectDiagnostics(testName, t.agentController())
+		}
+		deleteAgentConfig(&testGroupResult)
+		cleanUp(t.TestRunner, &testGroupResult)
+		testGroupResult.Duration = time.Since(startTime)
+	}()
+
+	testGroupResult, err := t.RunAgent(ctx)
 	if err == nil {
 		testGroupResult = t.TestRunner.Validate()
 	}
@@ -114,7 +183,7 @@ func (t *TestRunner) Run() status.TestGroupResult {
 	return testGroupResult
 }
 
-func (t *TestRunner) RunAgent() (status.TestGroupResult, error) {
+func (t *TestRunner) RunAgent(ctx context.Context) (status.TestGroupResult, error) {
 	testGroupResult := status.TestGroupResult{
 		Name: t.TestRunner.GetTestName(),
 		TestResults: []status.TestResult{
@@ -131,39 +200,43 @@ func (t *TestRunner) RunAgent() (status.TestGroupResult, error) {
 		UseSSM:           t.TestRunner.UseSSM(),
 	}
 	t.TestRunner.SetAgentConfig(agentConfig)
+	logMeasuredMetricsDrift(t.TestRunner)
 	err := t.TestRunner.SetupBeforeAgentRun()
 	if err != nil {
-		testGroupResult.TestResults[0].Status = status.FAILED
+		testGroupResult.TestResults[0].Fail("Failed to complete setup before agent run due to: %v", err)
 		return testGroupResult, fmt.Errorf("Failed to complete setup before agent run due to: %w", err)
 	}
 
-	if t.TestRunner.UseSSM() {
-		err = common.StartAgent(t.TestRunner.SSMParameterName(), false, true)
-	} else {
-		err = common.StartAgent(configOutputPath, false, false)
-	}
-
+	agentController := t.agentController()
+	err = startAgent(ctx, agentController, agentConfigLocation(t.TestRunner))
 	if err != nil {
-		testGroupResult.TestResults[0].Status = status.FAILED
+		testGroupResult.TestResults[0].Fail("Agent could not start due to: %v", err)
 		return testGroupResult, fmt.Errorf("Agent could not start due to: %w", err)
 	}
 
-	err = t.TestRunner.SetupAfterAgentRun()
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	err = t.TestRunner.SetupAfterAgentRun(runCtx)
 	if err != nil {
-		testGroupResult.TestResults[0].Status = status.FAILED
+		agentController.Stop()
+		testGroupResult.TestResults[0].Fail("Failed to complete setup after agent run due to: %v", err)
 		return testGroupResult, fmt.Errorf("Failed to complete setup after agent run due to: %w", err)
 	}
 
 	runningDuration := t.TestRunner.GetAgentRunDuration()
-	time.Sleep(runningDuration)
+	if err = common.Sleep(ctx, runningDuration); err != nil {
+		agentController.Stop()
+		testGroupResult.TestResults[0].Fail("Agent run was interrupted due to: %v", err)
+		return testGroupResult, fmt.Errorf("Agent run was interrupted due to: %w", err)
+	}
 	log.Printf("Agent has been running for : %s", runningDuration.String())
-	common.StopAgent()
-
-	err = common.DeleteFile(configOutputPath)
+	cancelRun()
+	err = agentController.Stop()
 	if err != nil {
-		testGroupResult.TestResults[0].Status = status.FAILED
-		return testGroupResult, fmt.Errorf("Failed to cleanup config file after agent run due to: %w", err)
+		testGroupResult.TestResults[0].Fail("Agent could not stop due to: %v", err)
+		return testGroupResult, fmt.Errorf("Agent could not stop due to: %w", err)
 	}
 
+	// The agent config is deleted by Run once the diagnostics of a failed validation have been collected
 	return testGroupResult, nil
 }
@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const (
+	agentStartTimeout = 5 * time.Minute
+	// testShutdownGracePeriod leaves time to stop the agent and clean up before go test kills the test binary
+	testShutdownGracePeriod = 2 * time.Minute
+)
+
+// deadliner is implemented by *testing.T
+type deadliner interface {
+	Deadline() (time.Time, bool)
+}
+
+// Context returns the context to run the test runners with. It is done on Ctrl-C and SIGTERM, and shortly before
+// the go test -timeout deadline, so the test runners stop the load and the agent and clean up instead of being
+// killed in the middle of the agent run.
+func Context(t deadliner) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	deadline, ok := t.Deadline()
+	if !ok {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline.Add(-testShutdownGracePeriod))
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func startAgent(ctx context.Context, agentController common.AgentController, configPath string) error {
+	startCtx, cancel := context.WithTimeout(ctx, agentStartTimeout)
+	defer cancel()
+	return agentController.Start(startCtx, configPath)
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"log"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/diagnostics"
+)
+
+// collectDiagnostics writes a diagnostics bundle for the failed test to the directory passed with the
+// diagnosticsDirectory flag and returns its location, or an empty string when no bundle was collected. The agent
+// config has to be deleted afterwards (see deleteAgentConfig) so the bundle has it.
+func collectDiagnostics(testName string, agentController common.AgentController) string {
+	env := environment.GetEnvironmentMetaData()
+	if env.DiagnosticsDirectory == "" {
+		return ""
+	}
+	location, err := diagnostics.Collect(diagnostics.Options{
+		Name:            testName,
+		Directory:       env.DiagnosticsDirectory,
+		Bucket:          env.DiagnosticsBucket,
+		MetaData:        env,
+		Agent:           agentController,
+		AgentConfigPath: agentConfigOutputPath(),
+	})
+	if err != nil {
+		log.Printf("Failed to collect the diagnostics of %s: %v", testName, err)
+	}
+	return location
+}
@@ -8,12 +8,12 @@ package test_runner
 import (
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/aws/amazon-cloudwatch-agent-test/environment"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 )
 
 type IAgentRunStrategy interface {
@@ -24,7 +24,7 @@ type ECSAgentRunStrategy struct {
 }
 
 func (r *ECSAgentRunStrategy) RunAgentStrategy(e *environment.MetaData, configFilePath string) error {
-	b, err := os.ReadFile(configFilePath)
+	b, err := common.RenderAgentConfig(configFilePath, common.NewAgentConfigVariables(e))
 	if err != nil {
 		return fmt.Errorf("Failed while reading config file")
 	}
@@ -57,6 +57,7 @@ type ECSTestRunner struct {
 func (t *ECSTestRunner) Run(s ITestSuite, e *environment.MetaData) {
 	name := t.Runner.GetTestName()
 	log.Printf("Running %s", name)
+	startTime := time.Now()
 
 	//runs agent restart with given config only when it's available
 	agentConfigFileName := t.Runner.GetAgentConfigFileName()
@@ -64,20 +65,26 @@ func (t *ECSTestRunner) Run(s ITestSuite, e *environment.MetaData) {
 		err := t.RunStrategy.RunAgentStrategy(e, t.Runner.GetAgentConfigFileName())
 		if err != nil {
 			log.Printf("Failed to run agent with config for the given testm err:%v", err)
-			s.AddToSuiteResult(status.TestGroupResult{
+			testGroupResult := status.TestGroupResult{
 				Name: t.Runner.GetTestName(),
 				TestResults: []status.TestResult{
 					{
 						Name:   "Starting Agent",
 						Status: status.FAILED,
+						Reason: err.Error(),
 					},
 				},
-			})
+				Duration: time.Since(startTime),
+			}
+			cleanUp(t.Runner, &testGroupResult)
+			s.AddToSuiteResult(testGroupResult)
 			return
 		}
 	}
 
 	testGroupResult := t.Runner.Validate()
+	cleanUp(t.Runner, &testGroupResult)
+	testGroupResult.Duration = time.Since(startTime)
 
 	s.AddToSuiteResult(testGroupResult)
 	if testGroupResult.GetStatus() != status.SUCCESSFUL {
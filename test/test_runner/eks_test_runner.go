@@ -21,10 +21,13 @@ type EKSTestRunner struct {
 func (t *EKSTestRunner) Run(s ITestSuite, e *environment.MetaData) {
 	name := t.Runner.GetTestName()
 	log.Printf("Running %s", name)
+	startTime := time.Now()
 	dur := t.Runner.GetAgentRunDuration()
 	time.Sleep(dur)
 
 	res := t.Runner.Validate()
+	cleanUp(t.Runner, &res)
+	res.Duration = time.Since(startTime)
 	s.AddToSuiteResult(res)
 	if res.GetStatus() != status.SUCCESSFUL {
 		log.Printf("%s test group failed", name)
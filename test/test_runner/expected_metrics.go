@@ -0,0 +1,256 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/metric"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/metric/dimension"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/agentconfig"
+)
+
+// defaultNamespace is the namespace of the agent configs without metrics.namespace
+const defaultNamespace = "CWAgent"
+
+// LoadAgentConfig renders and parses the agent config of the test runner
+func LoadAgentConfig(testRunner ITestRunner) (*agentconfig.Config, error) {
+	rendered, err := common.RenderAgentConfig(filepath.Join(agentConfigDirectory, testRunner.GetAgentConfigFileName()),
+		common.NewAgentConfigVariables(environment.GetEnvironmentMetaData()))
+	if err != nil {
+		return nil, err
+	}
+	return agentconfig.Parse(rendered)
+}
+
+// ExpectedMetrics returns the metrics the agent config of the test runner makes the agent send
+// (see agentconfig.Config.ExpectedMetrics)
+func ExpectedMetrics(testRunner ITestRunner) ([]agentconfig.ExpectedMetric, error) {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return nil, err
+	}
+	return config.ExpectedMetrics(), nil
+}
+
+// MeasuredMetricsFromAgentConfig returns the names of the ExpectedMetrics, so GetMeasuredMetrics does not have to
+// repeat the measurements of the agent config. Validate should read them with the error (see AgentConfigFailure)
+// rather than from GetMeasuredMetrics, which cannot return it.
+func MeasuredMetricsFromAgentConfig(testRunner ITestRunner) ([]string, error) {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return nil, err
+	}
+	return config.ExpectedMetricNames(), nil
+}
+
+// AgentConfigFailure returns the failed test group result of a test runner whose agent config cannot be read, so the
+// test group fails instead of validating no metrics
+func AgentConfigFailure(testRunner ITestRunner, err error) status.TestGroupResult {
+	return status.TestGroupResult{
+		Name:        testRunner.GetTestName(),
+		TestResults: status.FailAll([]string{testRunner.GetTestName() + "/agent_config"}, "failed to read the agent config: %v", err),
+	}
+}
+
+// AgentConfigAllowlist returns the allowlist of the metrics the agent config of the test runner sends with the scope
+// dimensions, bounding each metric to the dimension sets of the agent config which have the scope dimensions
+func AgentConfigAllowlist(testRunner ITestRunner, scope []types.Dimension) (metric.NamespaceAllowlist, error) {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return metric.NamespaceAllowlist{}, err
+	}
+	allowlist := metric.NamespaceAllowlist{Namespace: namespace(config), Scope: scope, MaxDimensionSets: make(map[string]int)}
+	for _, expectedMetric := range config.ExpectedMetrics() {
+		if _, ok := allowlist.MaxDimensionSets[expectedMetric.Name]; !ok {
+			allowlist.Expected = append(allowlist.Expected, expectedMetric.Name)
+			allowlist.MaxDimensionSets[expectedMetric.Name] = 0
+		}
+		for _, dimensionSet := range expectedMetric.DimensionSets {
+			if hasDimensions(dimensionSet, scope) {
+				allowlist.MaxDimensionSets[expectedMetric.Name]++
+			}
+		}
+	}
+	return allowlist, nil
+}
+
+// ExpectedUnits returns the unit of each metric the agent config of the test runner sends, the unit of the
+// measurement when the agent config sets one else the default unit of the metric (see metric.DefaultUnit). Metrics
+// with neither are left out.
+func ExpectedUnits(testRunner ITestRunner) (map[string]types.StandardUnit, error) {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return nil, err
+	}
+	units := make(map[string]types.StandardUnit)
+	for _, expectedMetric := range config.ExpectedMetrics() {
+		if expectedMetric.Unit != "" {
+			units[expectedMetric.Name] = types.StandardUnit(expectedMetric.Unit)
+		} else if unit, ok := metric.DefaultUnit(expectedMetric.Name); ok {
+			units[expectedMetric.Name] = unit
+		}
+	}
+	return units, nil
+}
+
+// ValidateUnits checks the unit of every data point of the metrics with an expected unit (see GetExpectedUnits), with
+// the dimensions the test runner resolved for them, in the namespace of its agent config
+func ValidateUnits(testRunner ITestRunner, dimensions []types.Dimension) []status.TestResult {
+	units, err := testRunner.GetExpectedUnits()
+	if err == nil && len(units) == 0 {
+		return nil
+	}
+	var config *agentconfig.Config
+	if err == nil {
+		config, err = LoadAgentConfig(testRunner)
+	}
+	if err != nil {
+		return status.FailAll([]string{testRunner.GetTestName() + "/units"}, "failed to read the expected units from the agent config: %v", err)
+	}
+	metricNames := make([]string, 0, len(units))
+	for metricName := range units {
+		metricNames = append(metricNames, metricName)
+	}
+	sort.Strings(metricNames)
+	checks := make([]metric.UnitCheck, 0, len(metricNames))
+	for _, metricName := range metricNames {
+		checks = append(checks, metric.UnitCheck{
+			Namespace:  namespace(config),
+			MetricName: metricName,
+			Dimensions: dimensions,
+			Unit:       units[metricName],
+		})
+	}
+	return metric.BatchValidator{}.ValidateUnits(context.Background(), checks)
+}
+
+// ValidateHighResolution validates the measured metrics of the test runner its agent config collects more often than
+// once a minute are stored at high resolution (see metric.HighResolutionChecks)
+func ValidateHighResolution(testRunner ITestRunner, dimensions []types.Dimension) []status.TestResult {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return []status.TestResult{{
+			Name:   testRunner.GetTestName() + "/high_resolution",
+			Status: status.FAILED,
+			Reason: fmt.Sprintf("failed to read the collection intervals from the agent config: %v", err),
+		}}
+	}
+	measured := make(map[string]struct{})
+	for _, metricName := range testRunner.GetMeasuredMetrics() {
+		measured[metricName] = struct{}{}
+	}
+	var checks []metric.MetricCheck
+	for _, expectedMetric := range config.ExpectedMetrics() {
+		if _, ok := measured[expectedMetric.Name]; !ok || expectedMetric.CollectionInterval >= 60 {
+			continue
+		}
+		checks = append(checks, metric.HighResolutionChecks(namespace(config), expectedMetric.Name, dimensions,
+			time.Duration(expectedMetric.CollectionInterval)*time.Second)...)
+	}
+	return metric.BatchValidator{}.Validate(context.Background(), checks)
+}
+
+// namespace returns the namespace the agent config sends the metrics to
+func namespace(config *agentconfig.Config) string {
+	if config.Metrics != nil && config.Metrics.Namespace != "" {
+		return config.Metrics.Namespace
+	}
+	return defaultNamespace
+}
+
+func hasDimensions(dimensionSet []agentconfig.Dimension, scope []types.Dimension) bool {
+	for _, scoped := range scope {
+		found := false
+		for _, d := range dimensionSet {
+			if d.Name == aws.ToString(scoped.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DimensionInstructions returns the instructions the DimensionFactory resolves the expected dimension set with
+func DimensionInstructions(dimensions []agentconfig.Dimension) []dimension.Instruction {
+	instructions := make([]dimension.Instruction, 0, len(dimensions))
+	for _, d := range dimensions {
+		value := dimension.UnknownDimensionValue()
+		if d.Value != nil {
+			value = dimension.ExpectedDimensionValue{Value: d.Value}
+		}
+		instructions = append(instructions, dimension.Instruction{Key: d.Name, Value: value})
+	}
+	return instructions
+}
+
+// DiffMeasuredMetrics returns the measured metrics of the test runner the agent config does not send (missing) and
+// the metrics the agent config sends which are not measured (unmeasured)
+func DiffMeasuredMetrics(testRunner ITestRunner) (missing []string, unmeasured []string, err error) {
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		return nil, nil, err
+	}
+	missing, unmeasured = diffMetrics(testRunner.GetMeasuredMetrics(), config.ExpectedMetricNames())
+	return missing, unmeasured, nil
+}
+
+func diffMetrics(measured []string, expected []string) (missing []string, unmeasured []string) {
+	expectedSet := make(map[string]struct{}, len(expected))
+	for _, name := range expected {
+		expectedSet[name] = struct{}{}
+	}
+	measuredSet := make(map[string]struct{}, len(measured))
+	for _, name := range measured {
+		measuredSet[name] = struct{}{}
+		if _, ok := expectedSet[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range expected {
+		if _, ok := measuredSet[name]; !ok {
+			unmeasured = append(unmeasured, name)
+		}
+	}
+	return missing, unmeasured
+}
+
+// logMeasuredMetricsDrift warns when the measured metrics of the test runner drifted from its agent config. The
+// test runners measuring metrics generated by the load (e.g statsd) have no metrics derived from the agent config
+// and the ones not measuring metrics by name have no measured metrics, neither are checked.
+func logMeasuredMetricsDrift(testRunner ITestRunner) {
+	if testRunner.GetAgentConfigFileName() == "" {
+		return
+	}
+	config, err := LoadAgentConfig(testRunner)
+	if err != nil {
+		log.Printf("Failed to compare the measured metrics of %s with its agent config: %v", testRunner.GetTestName(), err)
+		return
+	}
+	measured, expected := testRunner.GetMeasuredMetrics(), config.ExpectedMetricNames()
+	if len(measured) == 0 || len(expected) == 0 {
+		return
+	}
+	if missing, unmeasured := diffMetrics(measured, expected); len(missing) > 0 || len(unmeasured) > 0 {
+		log.Printf("Measured metrics of %s drifted from its agent config, not sent by the agent config: %v, not measured: %v",
+			testRunner.GetTestName(), missing, unmeasured)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"go.uber.org/multierr"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const cleanupTestResultName = "Cleanup"
+
+// Resource is something a test runner created which has to be released once the test runner is done
+type Resource struct {
+	Name    string
+	Release func() error
+}
+
+// ResourceRegistry keeps the resources created by the setup of a test runner. The resources are released
+// in the reverse order of their registration after the test runner ran, whether it failed or not.
+type ResourceRegistry struct {
+	resources []Resource
+}
+
+// Register records a resource with the function releasing it
+func (r *ResourceRegistry) Register(name string, release func() error) {
+	r.resources = append(r.resources, Resource{Name: name, Release: release})
+}
+
+// RegisterFile records a file to delete. Files which are already gone are not reported.
+func (r *ResourceRegistry) RegisterFile(path string) {
+	r.Register("file "+path, func() error {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+		return common.DeleteFile(path)
+	})
+}
+
+// RegisterLogGroup records a log group to delete
+func (r *ResourceRegistry) RegisterLogGroup(logGroupName string) {
+	r.Register("log group "+logGroupName, func() error {
+		if err := awsservice.DeleteLogGroupWithError(logGroupName); err != nil {
+			return err
+		}
+		if awsservice.IsLogGroupExists(logGroupName) {
+			return fmt.Errorf("log group %s still exists", logGroupName)
+		}
+		return nil
+	})
+}
+
+// RegisterSSMParameter records a SSM parameter to delete
+func (r *ResourceRegistry) RegisterSSMParameter(parameterName string) {
+	r.Register("ssm parameter "+parameterName, func() error {
+		return awsservice.DeleteParameter(parameterName)
+	})
+}
+
+// RegisterCommands records the commands undoing the setup of a resource. Every command runs even if
+// a previous one failed.
+func (r *ResourceRegistry) RegisterCommands(name string, commands []string) {
+	r.Register(name, func() error {
+		var errs error
+		for _, cmd := range commands {
+			if _, err := common.RunCommand(cmd); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("%s: %w", cmd, err))
+			}
+		}
+		return errs
+	})
+}
+
+// Release releases every registered resource and returns an error for each resource which could not be
+// released. The registry is empty afterwards.
+func (r *ResourceRegistry) Release() error {
+	var errs error
+	for i := len(r.resources) - 1; i >= 0; i-- {
+		resource := r.resources[i]
+		log.Printf("Releasing %s", resource.Name)
+		if err := resource.Release(); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to release %s: %w", resource.Name, err))
+		}
+	}
+	r.resources = nil
+	return errs
+}
+
+// deleteAgentConfig deletes the agent config copied by the setup once the diagnostics have been collected.
+// A failure is reported like the resources which could not be released.
+func deleteAgentConfig(testGroupResult *status.TestGroupResult) {
+	path := agentConfigOutputPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return
+	}
+	if err := common.DeleteFile(path); err != nil {
+		log.Printf("Failed to delete the agent config %s: %v", path, err)
+		testGroupResult.TestResults = append(testGroupResult.TestResults, status.TestResult{
+			Name:   cleanupTestResultName,
+			Status: status.FAILED,
+			Reason: fmt.Sprintf("failed to delete the agent config %s: %v", path, err),
+		})
+	}
+}
+
+// cleanUp runs the cleanup of the test runner and adds a failed test result for every resource which could
+// not be released
+func cleanUp(testRunner ITestRunner, testGroupResult *status.TestGroupResult) {
+	err := testRunner.Cleanup()
+	for _, cleanupErr := range multierr.Errors(err) {
+		log.Printf("%v cleanup failed: %v", testRunner.GetTestName(), cleanupErr)
+		testGroupResult.TestResults = append(testGroupResult.TestResults, status.TestResult{
+			Name:   cleanupTestResultName,
+			Status: status.FAILED,
+			Reason: cleanupErr.Error(),
+		})
+	}
+}
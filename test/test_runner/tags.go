@@ -0,0 +1,124 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+)
+
+// Tags the test runners declare with GetTags. The OS family (e.g linux), the architecture (e.g arm64), the compute
+// type (e.g ec2), the test name (e.g name:cpu) and the speed are added to them (see Tags).
+const (
+	TagFast = "fast"
+	TagSlow = "slow"
+	// TagNeedsRoot marks test runners whose setup runs commands with sudo
+	TagNeedsRoot = "needs-root"
+	// TagNeedsNetwork marks test runners downloading their dependencies from the internet
+	TagNeedsNetwork = "needs-network"
+	TagSharedRun    = "shared-run"
+
+	pluginTagPrefix = "plugin:"
+	nameTagPrefix   = "name:"
+	// slowAgentRunDuration is the agent run duration from which a test runner not declaring its speed is slow
+	slowAgentRunDuration = time.Minute
+)
+
+// PluginTag returns the tag of a test runner covering the agent plugin (e.g plugin:cpu)
+func PluginTag(plugin string) string {
+	return pluginTagPrefix + strings.ToLower(plugin)
+}
+
+// Tags returns the tags the test selector is matched against
+func Tags(env *environment.MetaData, testRunner ITestRunner) []string {
+	tags := []string{
+		runtime.GOOS,
+		runtime.GOARCH,
+		nameTagPrefix + strings.ToLower(testRunner.GetTestName()),
+	}
+	if env.ComputeType != "" {
+		tags = append(tags, strings.ToLower(string(env.ComputeType)))
+	}
+	if testRunner.CanShareAgentRun() {
+		tags = append(tags, TagSharedRun)
+	}
+	declared := testRunner.GetTags()
+	if !containsTag(declared, TagFast) && !containsTag(declared, TagSlow) {
+		if testRunner.GetAgentRunDuration() >= slowAgentRunDuration {
+			tags = append(tags, TagSlow)
+		} else {
+			tags = append(tags, TagFast)
+		}
+	}
+	for _, tag := range declared {
+		tags = append(tags, strings.ToLower(tag))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ShouldRun returns whether the test runner is selected by the plugins and excludedTests flags and the test selector
+func ShouldRun(env *environment.MetaData, testRunner ITestRunner) bool {
+	name := strings.ToLower(testRunner.GetTestName())
+	if env.EC2PluginTests != nil || env.ExcludedTests != nil {
+		_, shouldRun := env.EC2PluginTests[name]
+		_, shouldExclude := env.ExcludedTests[name]
+		if !shouldRun && (len(env.ExcludedTests) == 0 || shouldExclude) {
+			return false
+		}
+	}
+	return MatchesTestSelector(env, testRunner)
+}
+
+// MatchesTestSelector returns whether the test runner is selected by the test selector alone
+func MatchesTestSelector(env *environment.MetaData, testRunner ITestRunner) bool {
+	return env.TestSelector.Match(Tags(env, testRunner))
+}
+
+// SelectTestRunners returns the test runners to run (see ShouldRun). With the listTests flag, it prints the selected
+// test runners and returns none.
+func SelectTestRunners(env *environment.MetaData, testRunners []*TestRunner) []*TestRunner {
+	return selectTestRunners(env, testRunners, ShouldRun)
+}
+
+// SelectTestRunnersByTags is SelectTestRunners for the suites the plugins and excludedTests flags do not apply to
+// (their test runners do not cover a single plugin), the test runners are only matched against the test selector
+func SelectTestRunnersByTags(env *environment.MetaData, testRunners []*TestRunner) []*TestRunner {
+	return selectTestRunners(env, testRunners, MatchesTestSelector)
+}
+
+func selectTestRunners(env *environment.MetaData, testRunners []*TestRunner, shouldRun func(*environment.MetaData, ITestRunner) bool) []*TestRunner {
+	var selected []*TestRunner
+	for _, testRunner := range testRunners {
+		if shouldRun(env, testRunner.TestRunner) {
+			selected = append(selected, testRunner)
+		} else {
+			log.Printf("Skipping %s, it is not selected", testRunner.TestRunner.GetTestName())
+		}
+	}
+	if env.ListTests {
+		for _, testRunner := range selected {
+			fmt.Printf("%s\t%s\n", testRunner.TestRunner.GetTestName(), strings.Join(Tags(env, testRunner.TestRunner), ","))
+		}
+		return nil
+	}
+	return selected
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package test_runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+// TestRunnerGroup is a set of test runners validated against a single agent run started with their merged
+// agent configs. A group with a single test runner runs it alone like TestRunner.Run.
+type TestRunnerGroup struct {
+	TestRunners []*TestRunner
+	agentConfig map[string]interface{}
+}
+
+// GroupTestRunners puts the test runners that can share an agent run in the same group as long as their agent
+// configs merge without conflicts. Every other test runner gets a group of its own. The groups keep the order
+// of their first test runner.
+func GroupTestRunners(testRunners []*TestRunner) []*TestRunnerGroup {
+	var groups []*TestRunnerGroup
+	for _, testRunner := range testRunners {
+		if testRunner.TestRunner.CanShareAgentRun() && addToSharedGroup(groups, testRunner) {
+			continue
+		}
+		group := &TestRunnerGroup{TestRunners: []*TestRunner{testRunner}}
+		if testRunner.TestRunner.CanShareAgentRun() {
+			// Agent configs which cannot be read leave the agent config empty, so no other test runner joins the group
+			agentConfig, err := readAgentConfig(testRunner.TestRunner.GetAgentConfigFileName())
+			if err != nil {
+				log.Printf("%s will run alone since its agent config could not be read: %v", testRunner.TestRunner.GetTestName(), err)
+			}
+			group.agentConfig = agentConfig
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func addToSharedGroup(groups []*TestRunnerGroup, testRunner *TestRunner) bool {
+	agentConfig, err := readAgentConfig(testRunner.TestRunner.GetAgentConfigFileName())
+	if err != nil {
+		return false
+	}
+	for _, group := range groups {
+		if group.agentConfig == nil {
+			continue
+		}
+		merged, err := mergeAgentConfigs(group.agentConfig, agentConfig)
+		if err != nil {
+			log.Printf("%s cannot share an agent run with %s: %v", testRunner.TestRunner.GetTestName(), group.testNames(), err)
+			continue
+		}
+		group.agentConfig = merged
+		group.TestRunners = append(group.TestRunners, testRunner)
+		return true
+	}
+	return false
+}
+
+// Run starts the agent once with the merged agent config, runs it for the longest duration of the test runners
+// and returns the validation result of every test runner. The resources of every test runner are cleaned up
+// afterwards, even after a panic.
+func (g *TestRunnerGroup) Run(ctx context.Context) (results []status.TestGroupResult) {
+	if len(g.TestRunners) == 1 {
+		return []status.TestGroupResult{g.TestRunners[0].Run(ctx)}
+	}
+
+	log.Printf("Running %v with a shared agent run", g.testNames())
+	startTime := time.Now()
+	results = make([]status.TestGroupResult, len(g.TestRunners))
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%v test groups panicked: %v\n%s", g.testNames(), r, debug.Stack())
+			if err := g.agentController().Stop(); err != nil {
+				log.Printf("Failed to stop agent after %v test groups panicked: %v", g.testNames(), err)
+			}
+			for i, testRunner := range g.TestRunners {
+				if results[i].Name == "" {
+					results[i] = status.TestGroupResult{
+						Name: testRunner.TestRunner.GetTestName(),
+						TestResults: []status.TestResult{
+							{Name: testRunner.TestRunner.GetTestName(), Status: status.FAILED, Reason: fmt.Sprintf("panic: %v", r)},
+						},
+						Duration: time.Since(startTime),
+					}
+				}
+			}
+		}
+		// The test runners share the agent, so a single diagnostics bundle covers every failed one
+		var (
+			diagnosticsLocation string
+			collected           bool
+		)
+		for i := range results {
+			if results[i].GetStatus() != status.SUCCESSFUL {
+				if !collected {
+					diagnosticsLocation = collectDiagnostics(strings.Join(g.testNames(), "_"), g.agentController())
+					collected = true
+				}
+				results[i].Diagnostics = diagnosticsLocation
+			}
+		}
+		// The test runners share the agent config as well
+		deleteAgentConfig(&results[0])
+		for i, testRunner := range g.TestRunners {
+			cleanUp(testRunner.TestRunner, &results[i])
+		}
+	}()
+
+	agentResult, err := g.RunAgent(ctx)
+	agentRunDuration := time.Since(startTime)
+
+	for i, testRunner := range g.TestRunners {
+		testName := testRunner.TestRunner.GetTestName()
+		if err != nil {
+			log.Printf("%v test group failed due to %v", testName, err)
+			results[i] = status.TestGroupResult{
+				Name:        testName,
+				TestResults: []status.TestResult{agentResult},
+				Duration:    agentRunDuration,
+			}
+			continue
+		}
+
+		validateStartTime := time.Now()
+		testGroupResult := testRunner.TestRunner.Validate()
+		testGroupResult.Duration = agentRunDuration + time.Since(validateStartTime)
+		if testGroupResult.GetStatus() != status.SUCCESSFUL {
+			log.Printf("%v test group failed", testName)
+		}
+		results[i] = testGroupResult
+	}
+	return results
+}
+
+// RunAgent runs the setup of every test runner before replacing the agent config with the merged agent config
+func (g *TestRunnerGroup) RunAgent(ctx context.Context) (status.TestResult, error) {
+	testResult := status.TestResult{
+		Name:   "Starting Agent",
+		Status: status.SUCCESSFUL,
+	}
+
+	var runningDuration time.Duration
+	for _, testRunner := range g.TestRunners {
+		testRunner.TestRunner.SetAgentConfig(AgentConfig{ConfigFileName: testRunner.TestRunner.GetAgentConfigFileName()})
+		logMeasuredMetricsDrift(testRunner.TestRunner)
+		if err := testRunner.TestRunner.SetupBeforeAgentRun(); err != nil {
+			testResult.Fail("Failed to complete setup of %s before agent run due to: %v", testRunner.TestRunner.GetTestName(), err)
+			return testResult, fmt.Errorf("Failed to complete setup of %s before agent run due to: %w", testRunner.TestRunner.GetTestName(), err)
+		}
+		if duration := testRunner.TestRunner.GetAgentRunDuration(); duration > runningDuration {
+			runningDuration = duration
+		}
+	}
+
+	if err := g.writeAgentConfig(); err != nil {
+		testResult.Fail("Failed to write the merged agent config due to: %v", err)
+		return testResult, fmt.Errorf("Failed to write the merged agent config due to: %w", err)
+	}
+
+	agentController := g.agentController()
+	if err := startAgent(ctx, agentController, agentConfigOutputPath()); err != nil {
+		testResult.Fail("Agent could not start due to: %v", err)
+		return testResult, fmt.Errorf("Agent could not start due to: %w", err)
+	}
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+	for _, testRunner := range g.TestRunners {
+		if err := testRunner.TestRunner.SetupAfterAgentRun(runCtx); err != nil {
+			agentController.Stop()
+			testResult.Fail("Failed to complete setup of %s after agent run due to: %v", testRunner.TestRunner.GetTestName(), err)
+			return testResult, fmt.Errorf("Failed to complete setup of %s after agent run due to: %w", testRunner.TestRunner.GetTestName(), err)
+		}
+	}
+
+	if err := common.Sleep(ctx, runningDuration); err != nil {
+		agentController.Stop()
+		testResult.Fail("Agent run was interrupted due to: %v", err)
+		return testResult, fmt.Errorf("Agent run was interrupted due to: %w", err)
+	}
+	log.Printf("Agent has been running for : %s", runningDuration.String())
+	cancelRun()
+	if err := agentController.Stop(); err != nil {
+		testResult.Fail("Agent could not stop due to: %v", err)
+		return testResult, fmt.Errorf("Agent could not stop due to: %w", err)
+	}
+	return testResult, nil
+}
+
+// writeAgentConfig replaces the agent config copied by the setup of the test runners with the merged agent config
+func (g *TestRunnerGroup) writeAgentConfig() error {
+	agentConfig, err := json.MarshalIndent(g.agentConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "merged_agent_config_*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(agentConfig); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	log.Printf("Starting agent using merged agent config of %v", g.testNames())
+	common.CopyFile(file.Name(), agentConfigOutputPath())
+	return nil
+}
+
+// agentController is the agent controller of the first test runner since the test runners share the agent
+func (g *TestRunnerGroup) agentController() common.AgentController {
+	return g.TestRunners[0].agentController()
+}
+
+func (g *TestRunnerGroup) testNames() []string {
+	testNames := make([]string, 0, len(g.TestRunners))
+	for _, testRunner := range g.TestRunners {
+		testNames = append(testNames, testRunner.TestRunner.GetTestName())
+	}
+	return testNames
+}
+
+func readAgentConfig(agentConfigFileName string) (map[string]interface{}, error) {
+	agentConfigBytes, err := common.RenderAgentConfig(filepath.Join(agentConfigDirectory, agentConfigFileName),
+		common.NewAgentConfigVariables(environment.GetEnvironmentMetaData()))
+	if err != nil {
+		return nil, err
+	}
+	var agentConfig map[string]interface{}
+	if err = json.Unmarshal(agentConfigBytes, &agentConfig); err != nil {
+		return nil, err
+	}
+	return agentConfig, nil
+}
+
+// mergeAgentConfigs returns an agent config collecting the metrics of both agent configs. Everything besides
+// metrics.metrics_collected has to be the same in both agent configs and a plugin in both agent configs has to
+// be configured the same way, otherwise the metrics would not be collected the way the test runners expect.
+func mergeAgentConfigs(a, b map[string]interface{}) (map[string]interface{}, error) {
+	merged, aPlugins := splitPlugins(a)
+	bRest, bPlugins := splitPlugins(b)
+	if !reflect.DeepEqual(merged, bRest) {
+		return nil, errors.New("agent configs differ outside of metrics.metrics_collected")
+	}
+
+	plugins := map[string]interface{}{}
+	for name, plugin := range aPlugins {
+		plugins[name] = plugin
+	}
+	for name, plugin := range bPlugins {
+		if existing, ok := plugins[name]; ok && !reflect.DeepEqual(existing, plugin) {
+			return nil, fmt.Errorf("plugin %s is configured differently", name)
+		}
+		plugins[name] = plugin
+	}
+
+	metrics, ok := merged["metrics"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("agent configs have no metrics section")
+	}
+	metrics["metrics_collected"] = plugins
+	return merged, nil
+}
+
+// splitPlugins returns a copy of the agent config without metrics.metrics_collected and the plugins configured in it
+func splitPlugins(agentConfig map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	rest := map[string]interface{}{}
+	for key, value := range agentConfig {
+		rest[key] = value
+	}
+	metrics, ok := agentConfig["metrics"].(map[string]interface{})
+	if !ok {
+		return rest, nil
+	}
+
+	metricsRest := map[string]interface{}{}
+	for key, value := range metrics {
+		metricsRest[key] = value
+	}
+	plugins, _ := metricsRest["metrics_collected"].(map[string]interface{})
+	delete(metricsRest, "metrics_collected")
+	rest["metrics"] = metricsRest
+	return rest, plugins
+}
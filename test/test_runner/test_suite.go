@@ -7,7 +7,9 @@ package test_runner
 
 import (
 	"fmt"
+	"log"
 
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/status"
 )
 
@@ -30,9 +32,20 @@ func (suite *TestSuite) SetupSuite() {
 
 func (suite *TestSuite) TearDownSuite() {
 	suite.Result.Print()
+	suite.WriteReports(suite.GetSuiteName())
 	fmt.Printf(">>>> Finished %s TestSuite", suite.GetSuiteName())
 }
 
+// WriteReports writes the suite result reports to the directory passed with the reportDirectory flag
+func (suite *TestSuite) WriteReports(suiteName string) {
+	if suite.Result.Name == "" {
+		suite.Result.Name = suiteName
+	}
+	if err := suite.Result.WriteReports(environment.GetEnvironmentMetaData().ReportDirectory); err != nil {
+		log.Printf("Failed to write the test reports for %s: %v", suiteName, err)
+	}
+}
+
 func (suite *TestSuite) GetSuiteName() string {
 	return "Base"
 }
@@ -59,6 +59,36 @@ func DeleteStack(ctx context.Context, stackName string, client *cloudformation.C
 	}
 }
 
+// DeleteStackWithError starts the deletion of the stack and returns the error instead of exiting
+func DeleteStackWithError(ctx context.Context, stackName string, client *cloudformation.Client) error {
+	_, err := client.DeleteStack(ctx, &cloudformation.DeleteStackInput{
+		StackName: aws.String(stackName),
+	})
+	return err
+}
+
+// ListStacks returns every stack which has not been deleted yet
+func ListStacks(ctx context.Context, client *cloudformation.Client) ([]types.StackSummary, error) {
+	var stacks []types.StackSummary
+	input := &cloudformation.ListStacksInput{}
+	for {
+		output, err := client.ListStacks(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, stack := range output.StackSummaries {
+			if stack.StackStatus != types.StackStatusDeleteComplete {
+				stacks = append(stacks, stack)
+			}
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return stacks, nil
+}
+
 func FindStackInstanceId(ctx context.Context, stackName string, client *cloudformation.Client, timeOutMinutes int) string {
 	for i := 0; i <= timeOutMinutes; i++ {
 		cfStackInput := cloudformation.DescribeStacksInput{
@@ -48,20 +48,52 @@ func DeleteLogStream(logGroupName, logStreamName string) {
 
 // DeleteLogGroup cleans up log group by name
 func DeleteLogGroup(logGroupName string) {
+	if err := DeleteLogGroupWithError(logGroupName); err != nil {
+		log.Printf("Error occurred while deleting log group %s: %v", logGroupName, err)
+	}
+}
+
+// DeleteLogGroupWithError deletes the log group by name and returns the error, a log group which does not
+// exist is not an error
+func DeleteLogGroupWithError(logGroupName string) error {
 	_, err := CwlClient.DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{
 		LogGroupName: aws.String(logGroupName),
 	})
 	if err != nil && !errors.As(err, &rnf) {
-		log.Printf("Error occurred while deleting log group %s: %v", logGroupName, err)
+		return err
+	}
+	return nil
+}
+
+// ListLogGroups returns every log group whose name starts with the prefix
+func ListLogGroups(logGroupNamePrefix string) ([]types.LogGroup, error) {
+	var logGroups []types.LogGroup
+	input := &cloudwatchlogs.DescribeLogGroupsInput{LogGroupNamePrefix: aws.String(logGroupNamePrefix)}
+	for {
+		output, err := CwlClient.DescribeLogGroups(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		logGroups = append(logGroups, output.LogGroups...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
 	}
+	return logGroups, nil
 }
 
 // ValidateLogs queries a given LogGroup/LogStream combination given the start and end times, and executes an
 // arbitrary validator function on the found logs.
 func ValidateLogs(logGroup, logStream string, since, until *time.Time, validators ...LogEventsValidator) error {
+	return ValidateLogsWithContext(ctx, logGroup, logStream, since, until, validators...)
+}
+
+// ValidateLogsWithContext is ValidateLogs which stops querying and waiting for the log stream when the context is done
+func ValidateLogsWithContext(ctx context.Context, logGroup, logStream string, since, until *time.Time, validators ...LogEventsValidator) error {
 	log.Printf("Checking %s/%s", logGroup, logStream)
 
-	events, err := getLogsSince(logGroup, logStream, since, until)
+	events, err := getLogsSince(ctx, logGroup, logStream, since, until)
 	if err != nil {
 		return err
 	}
@@ -76,7 +108,7 @@ func ValidateLogs(logGroup, logStream string, since, until *time.Time, validator
 
 // getLogsSince makes GetLogEvents API calls, paginates through the results for the given time frame, and returns
 // the raw log strings
-func getLogsSince(logGroup, logStream string, since, until *time.Time) ([]types.OutputLogEvent, error) {
+func getLogsSince(ctx context.Context, logGroup, logStream string, since, until *time.Time) ([]types.OutputLogEvent, error) {
 	var events []types.OutputLogEvent
 
 	// https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_GetLogEvents.html
@@ -110,8 +142,12 @@ func getLogsSince(logGroup, logStream string, since, until *time.Time) ([]types.
 		if err != nil {
 			if errors.As(err, &rnf) && attempts <= StandardRetries {
 				// The log group/stream hasn't been created yet, so wait and retry
-				time.Sleep(30 * time.Second)
-				continue
+				select {
+				case <-time.After(30 * time.Second):
+					continue
+				case <-ctx.Done():
+					return events, ctx.Err()
+				}
 			}
 
 			// if the error is not a ResourceNotFoundException, we should fail here.
@@ -325,7 +361,7 @@ func AssertNoDuplicateLogs() LogEventsValidator {
 
 func GetLogEventCountPerType(logGroup, logStream string, since, until *time.Time) (map[string]int, error) {
 	var typeFrequency = make(map[string]int)
-	events, err := getLogsSince(logGroup, logStream, since, until)
+	events, err := getLogsSince(ctx, logGroup, logStream, since, until)
 
 	// if there is an error, return the empty map
 	if err != nil {
@@ -4,6 +4,7 @@
 package awsservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -75,6 +76,12 @@ func ValidateMetricWithTest(t *testing.T, metricName, namespace string, dimensio
 func ValidateSampleCount(metricName, namespace string, dimensions []types.Dimension,
 	startTime time.Time, endTime time.Time,
 	lowerBoundInclusive int, upperBoundInclusive int, periodInSeconds int32) bool {
+	return ValidateSampleCountWithContext(ctx, metricName, namespace, dimensions, startTime, endTime, lowerBoundInclusive, upperBoundInclusive, periodInSeconds)
+}
+
+func ValidateSampleCountWithContext(ctx context.Context, metricName, namespace string, dimensions []types.Dimension,
+	startTime time.Time, endTime time.Time,
+	lowerBoundInclusive int, upperBoundInclusive int, periodInSeconds int32) bool {
 
 	metricStatsInput := cloudwatch.GetMetricStatisticsInput{
 		MetricName: aws.String(metricName),
@@ -115,6 +122,20 @@ func GetMetricStatistics(
 	periodInSeconds int32,
 	statType []types.Statistic,
 	extendedStatType []string,
+) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	return GetMetricStatisticsWithContext(ctx, metricName, namespace, dimensions, startTime, endTime, periodInSeconds, statType, extendedStatType)
+}
+
+func GetMetricStatisticsWithContext(
+	ctx context.Context,
+	metricName string,
+	namespace string,
+	dimensions []types.Dimension,
+	startTime time.Time,
+	endTime time.Time,
+	periodInSeconds int32,
+	statType []types.Statistic,
+	extendedStatType []string,
 ) (*cloudwatch.GetMetricStatisticsOutput, error) {
 	metricStatsInput := cloudwatch.GetMetricStatisticsInput{
 		MetricName: aws.String(metricName),
@@ -136,6 +157,10 @@ func GetMetricStatistics(
 
 // GetMetricData takes the metric name, metric dimension and metric namespace and return the query metrics
 func GetMetricData(metricDataQueries []types.MetricDataQuery, startTime, endTime time.Time) (*cloudwatch.GetMetricDataOutput, error) {
+	return GetMetricDataWithContext(ctx, metricDataQueries, startTime, endTime)
+}
+
+func GetMetricDataWithContext(ctx context.Context, metricDataQueries []types.MetricDataQuery, startTime, endTime time.Time) (*cloudwatch.GetMetricDataOutput, error) {
 	getMetricDataInput := cloudwatch.GetMetricDataInput{
 		StartTime:         &startTime,
 		EndTime:           &endTime,
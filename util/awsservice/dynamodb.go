@@ -93,3 +93,50 @@ func GetItemInDatabase(databaseName, indexName string, checkingAttribute, checki
 
 	return packets[0], nil
 }
+
+// ScanItemsInDatabase returns every item whose string attribute starts with the prefix
+func ScanItemsInDatabase(databaseName, attribute, prefix string) ([]map[string]interface{}, error) {
+	var items []map[string]interface{}
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(databaseName),
+		FilterExpression: aws.String("begins_with(#attribute, :prefix)"),
+		ExpressionAttributeNames: map[string]string{
+			"#attribute": attribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prefix": &types.AttributeValueMemberS{Value: prefix},
+		},
+	}
+	for {
+		output, err := DynamodbClient.Scan(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		var page []map[string]interface{}
+		if err = attributevalue.UnmarshalListOfMaps(output.Items, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+	return items, nil
+}
+
+// DeleteItemInDatabase deletes the item with the primary key
+func DeleteItemInDatabase(databaseName string, key map[string]interface{}) error {
+	itemKey, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = DynamodbClient.DeleteItem(ctx,
+		&dynamodb.DeleteItemInput{
+			Key:       itemKey,
+			TableName: aws.String(databaseName),
+		})
+
+	return err
+}
@@ -31,3 +31,24 @@ func DownloadFile(bucket, key, outFilename string) error {
 	}
 	return err
 }
+
+func UploadFile(bucket, key, inFilename string) error {
+	log.Printf("uploading %s to %s, %s...", inFilename, bucket, key)
+	file, err := os.Open(inFilename)
+	if err != nil {
+		log.Printf("error: opening file %s err %v", inFilename, err)
+		return err
+	}
+	defer file.Close()
+	s3PutObjectInput := s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}
+	uploader := manager.NewUploader(S3Client)
+	_, err = uploader.Upload(ctx, &s3PutObjectInput)
+	if err != nil {
+		log.Printf("error: uploading, %v", err)
+	}
+	return err
+}
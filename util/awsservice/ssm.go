@@ -4,6 +4,8 @@
 package awsservice
 
 import (
+	"errors"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
@@ -24,6 +26,44 @@ func GetStringParameter(name string) string {
 	return *parameter.Parameter.Value
 }
 
+// ListParameters returns the metadata of every parameter whose name starts with the prefix
+func ListParameters(namePrefix string) ([]types.ParameterMetadata, error) {
+	var parameters []types.ParameterMetadata
+	input := &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{
+			{
+				Key:    aws.String("Name"),
+				Option: aws.String("BeginsWith"),
+				Values: []string{namePrefix},
+			},
+		},
+	}
+	for {
+		output, err := SsmClient.DescribeParameters(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, output.Parameters...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return parameters, nil
+}
+
+// DeleteParameter deletes the parameter by name and ignores parameters which do not exist
+func DeleteParameter(name string) error {
+	_, err := SsmClient.DeleteParameter(ctx, &ssm.DeleteParameterInput{
+		Name: aws.String(name),
+	})
+	var notFound *types.ParameterNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	return nil
+}
+
 func putParameter(name, value string, paramType types.ParameterType) error {
 	isOverwriteAllowed := true
 
@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+)
+
+const (
+	localStackHostEnvVar  = "LOCAL_STACK_HOST_NAME"
+	defaultLocalStackHost = "localhost.localstack.cloud"
+)
+
+// AgentConfigVariables are the variables agent configs can use as Go templates, e.g
+//
+//	"namespace": "{{ .Namespace `MetricValueBenchmarkTest` }}",
+//	"log_group_name": "{{ .LogGroup `emf-test` }}",
+//	"endpoint_override": "https://{{ .LocalStackHost }}:4566"
+//
+// Agent configs without template actions are rendered as they are.
+type AgentConfigVariables struct {
+	// RunId is unique to the test run unless set with the runId flag
+	RunId              string
+	CaCertPath         string
+	ProxyUrl           string
+	AmpWorkspaceId     string
+	MockServerEndpoint string
+	// LocalStackHost is read from LOCAL_STACK_HOST_NAME
+	LocalStackHost string
+
+	instanceId string
+}
+
+func NewAgentConfigVariables(env *environment.MetaData) AgentConfigVariables {
+	localStackHost := os.Getenv(localStackHostEnvVar)
+	if localStackHost == "" {
+		localStackHost = defaultLocalStackHost
+	}
+	return AgentConfigVariables{
+		RunId:              env.RunId,
+		CaCertPath:         env.CaCertPath,
+		ProxyUrl:           env.ProxyUrl,
+		AmpWorkspaceId:     env.AmpWorkspaceId,
+		MockServerEndpoint: env.MockServerEndpoint,
+		LocalStackHost:     localStackHost,
+		instanceId:         env.InstanceId,
+	}
+}
+
+// InstanceId returns the instanceId flag or the instance id from IMDS, which is only queried by the agent configs using it
+func (v AgentConfigVariables) InstanceId() string {
+	if v.instanceId != "" {
+		return v.instanceId
+	}
+	return awsservice.GetInstanceId()
+}
+
+// Namespace returns the CloudWatch namespace of the test run. Validators query the same name.
+func (v AgentConfigVariables) Namespace(name string) string {
+	return v.runScoped(name)
+}
+
+// LogGroup returns the log group name of the test run. Validators query the same name.
+func (v AgentConfigVariables) LogGroup(name string) string {
+	return v.runScoped(name)
+}
+
+// RunNamespace returns the namespace agent configs render {{ .Namespace name }} to in this test run, so validators
+// query the namespace the agent sent the metrics to
+func RunNamespace(name string) string {
+	return NewAgentConfigVariables(environment.GetEnvironmentMetaData()).Namespace(name)
+}
+
+// RunLogGroup returns the log group name agent configs render {{ .LogGroup name }} to in this test run
+func RunLogGroup(name string) string {
+	return NewAgentConfigVariables(environment.GetEnvironmentMetaData()).LogGroup(name)
+}
+
+func (v AgentConfigVariables) runScoped(name string) string {
+	if v.RunId == "" {
+		return name
+	}
+	return name + "-" + v.RunId
+}
+
+// RenderAgentConfig renders the agent config template at the path and checks the result is still valid JSON
+func RenderAgentConfig(templatePath string, variables AgentConfigVariables) ([]byte, error) {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(templatePath)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent config template %s: %w", templatePath, err)
+	}
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, variables); err != nil {
+		return nil, fmt.Errorf("failed to render agent config template %s: %w", templatePath, err)
+	}
+	if !json.Valid(rendered.Bytes()) {
+		return nil, fmt.Errorf("agent config template %s is not valid JSON once rendered", templatePath)
+	}
+	return rendered.Bytes(), nil
+}
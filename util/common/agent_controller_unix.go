@@ -0,0 +1,336 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/environment/agentcontrollertype"
+)
+
+const (
+	// SSMConfigPrefix marks a config path as a SSM parameter name, which only the CtlAgentController supports
+	SSMConfigPrefix = "ssm:"
+
+	agentCtlPath          = "/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl"
+	agentBinaryName       = "amazon-cloudwatch-agent"
+	translatorBinaryName  = "config-translator"
+	agentStopTimeout      = 30 * time.Second
+	defaultContainerName  = "cloudwatch-agent-test"
+	containerConfigPath   = "/etc/cwagentconfig/config.json"
+	agentNotRunningStatus = "stopped"
+)
+
+// AgentController starts and stops the agent under test and exposes what the tests need to inspect it
+type AgentController interface {
+	// Start starts the agent with the agent config at the path. The context only bounds starting the agent,
+	// the agent keeps running until Stop.
+	Start(ctx context.Context, configPath string) error
+	// Stop does not take a context since the agent has to be stopped after the test is interrupted as well
+	Stop() error
+	// Restart stops the agent and starts it again with the agent config at the path
+	Restart(ctx context.Context, configPath string) error
+	// Status returns the agent status as reported by the controller (e.g running or stopped)
+	Status() (string, error)
+	// Logs returns the agent log
+	Logs() (string, error)
+	// Pid returns the agent process id
+	Pid() (int, error)
+}
+
+// NewAgentController returns the agent controller selected with the agentController flag
+func NewAgentController(env *environment.MetaData) AgentController {
+	switch env.AgentController {
+	case agentcontrollertype.BINARY:
+		return &BinaryAgentController{Directory: env.AgentBinaryDirectory}
+	case agentcontrollertype.DOCKER:
+		return &DockerAgentController{Image: env.AgentImage}
+	default:
+		return &CtlAgentController{StartCommand: env.AgentStartCommand}
+	}
+}
+
+func restart(ctx context.Context, controller AgentController, configPath string) error {
+	if err := controller.Stop(); err != nil {
+		return err
+	}
+	return controller.Start(ctx, configPath)
+}
+
+// CtlAgentController manages the agent installed by the package through amazon-cloudwatch-agent-ctl and systemd
+type CtlAgentController struct {
+	// StartCommand is the fetch-config command the config path is appended to (e.g DefaultEC2AgentStartCommand)
+	StartCommand string
+}
+
+var _ AgentController = (*CtlAgentController)(nil)
+
+func (c *CtlAgentController) Start(ctx context.Context, configPath string) error {
+	if strings.HasPrefix(configPath, SSMConfigPrefix) {
+		return startAgent(ctx, strings.TrimPrefix(configPath, SSMConfigPrefix), false, true, c.StartCommand)
+	}
+	return startAgent(ctx, configPath, false, false, c.StartCommand)
+}
+
+func (c *CtlAgentController) Stop() error {
+	return stopAgent()
+}
+
+func (c *CtlAgentController) Restart(ctx context.Context, configPath string) error {
+	return restart(ctx, c, configPath)
+}
+
+func (c *CtlAgentController) Status() (string, error) {
+	return RunCommand("sudo " + agentCtlPath + " -a status")
+}
+
+func (c *CtlAgentController) Logs() (string, error) {
+	return RunCommand(CatCommand + AgentLogFile)
+}
+
+func (c *CtlAgentController) Pid() (int, error) {
+	out, err := RunCommand("systemctl show --property MainPID --value amazon-cloudwatch-agent")
+	if err != nil {
+		return 0, err
+	}
+	return parsePid(out)
+}
+
+// BinaryAgentController translates the agent config and runs the agent binary directly as a child process, so
+// an agent build can be tested without installing the package
+type BinaryAgentController struct {
+	// Directory has the amazon-cloudwatch-agent and config-translator binaries. The translated agent configs
+	// and the agent output are written to it as well.
+	Directory string
+	// Mode is the config translator mode (default ec2)
+	Mode string
+
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	logPath string
+}
+
+var _ AgentController = (*BinaryAgentController)(nil)
+
+func (c *BinaryAgentController) Start(ctx context.Context, configPath string) error {
+	if strings.HasPrefix(configPath, SSMConfigPrefix) {
+		return errors.New("the binary agent controller does not support agent configs from ssm")
+	}
+	if c.cmd != nil {
+		return errors.New("the agent is already running")
+	}
+
+	mode := c.Mode
+	if mode == "" {
+		mode = "ec2"
+	}
+	var (
+		tomlPath      = filepath.Join(c.Directory, "amazon-cloudwatch-agent.toml")
+		yamlPath      = filepath.Join(c.Directory, "amazon-cloudwatch-agent.yaml")
+		envConfigPath = filepath.Join(c.Directory, "env-config.json")
+	)
+	translate := exec.CommandContext(ctx, filepath.Join(c.Directory, translatorBinaryName),
+		"--input", configPath,
+		"--input-dir", filepath.Join(c.Directory, "amazon-cloudwatch-agent.d"),
+		"--output", tomlPath,
+		"--mode", mode,
+		"--multi-config", "default")
+	translate.Dir = c.Directory
+	if out, err := translate.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to translate agent config %s: %w: %s", configPath, err, out)
+	}
+
+	c.logPath = filepath.Join(c.Directory, "amazon-cloudwatch-agent.out")
+	logFile, err := os.Create(c.logPath)
+	if err != nil {
+		return err
+	}
+	// The agent outlives the start context
+	cmd := exec.Command(filepath.Join(c.Directory, agentBinaryName),
+		"-config", tomlPath,
+		"-otelconfig", yamlPath,
+		"-envconfig", envConfigPath)
+	cmd.Dir = c.Directory
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err = cmd.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+	log.Printf("Started agent binary with pid %d", cmd.Process.Pid)
+
+	c.cmd = cmd
+	c.exited = make(chan struct{})
+	go func() {
+		cmd.Wait()
+		logFile.Close()
+		close(c.exited)
+	}()
+	return nil
+}
+
+func (c *BinaryAgentController) Stop() error {
+	if c.cmd == nil {
+		return nil
+	}
+	defer func() { c.cmd = nil }()
+	if err := c.cmd.Process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	select {
+	case <-c.exited:
+	case <-time.After(agentStopTimeout):
+		log.Printf("Agent did not stop within %v, killing it", agentStopTimeout)
+		if err := c.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+		<-c.exited
+	}
+	log.Printf("Agent is stopped")
+	return nil
+}
+
+func (c *BinaryAgentController) Restart(ctx context.Context, configPath string) error {
+	return restart(ctx, c, configPath)
+}
+
+func (c *BinaryAgentController) Status() (string, error) {
+	if c.cmd == nil {
+		return agentNotRunningStatus, nil
+	}
+	select {
+	case <-c.exited:
+		return fmt.Sprintf("exited: %v", c.cmd.ProcessState), nil
+	default:
+		return "running", nil
+	}
+}
+
+// Logs returns the agent output. The agent log goes there unless the agent config sets agent.logfile.
+func (c *BinaryAgentController) Logs() (string, error) {
+	if c.logPath == "" {
+		return "", errors.New("the agent has not been started")
+	}
+	out, err := os.ReadFile(c.logPath)
+	return string(out), err
+}
+
+func (c *BinaryAgentController) Pid() (int, error) {
+	if c.cmd == nil {
+		return 0, errors.New("the agent is not running")
+	}
+	return c.cmd.Process.Pid, nil
+}
+
+// DockerAgentController runs the agent image in a local container using the host network, so the tests can
+// send data to the agent and read its metrics the same way as with an installed agent
+type DockerAgentController struct {
+	Image string
+	// ContainerName defaults to cloudwatch-agent-test
+	ContainerName string
+	// RunArgs are extra docker run arguments (e.g mounting ~/.aws for credentials outside of EC2)
+	RunArgs []string
+}
+
+var _ AgentController = (*DockerAgentController)(nil)
+
+func (c *DockerAgentController) Start(ctx context.Context, configPath string) error {
+	if strings.HasPrefix(configPath, SSMConfigPrefix) {
+		return errors.New("the docker agent controller does not support agent configs from ssm")
+	}
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+	// A container left over by an earlier run (e.g an interrupted one) would keep the name taken
+	out, err := exec.CommandContext(ctx, "docker", "rm", "--force", c.containerName()).CombinedOutput()
+	if err != nil && !isNoSuchContainer(string(out)) {
+		return fmt.Errorf("failed to remove leftover agent container: %w: %s", err, out)
+	}
+	args := []string{"run", "--detach", "--name", c.containerName(), "--network", "host",
+		"--volume", absConfigPath + ":" + containerConfigPath + ":ro"}
+	args = append(args, c.RunArgs...)
+	args = append(args, c.Image)
+	out, err = exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to start agent container: %w: %s", err, out)
+	}
+	log.Printf("Started agent container %s", strings.TrimSpace(string(out)))
+	return nil
+}
+
+func (c *DockerAgentController) Stop() error {
+	out, err := exec.Command("docker", "rm", "--force", c.containerName()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove agent container: %w: %s", err, out)
+	}
+	log.Printf("Agent is stopped")
+	return nil
+}
+
+func (c *DockerAgentController) Restart(ctx context.Context, configPath string) error {
+	return restart(ctx, c, configPath)
+}
+
+func (c *DockerAgentController) Status() (string, error) {
+	out, err := exec.Command("docker", "inspect", "--format", "{{.State.Status}}", c.containerName()).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && isNoSuchContainer(string(exitErr.Stderr)) {
+			return agentNotRunningStatus, nil
+		}
+		return "", fmt.Errorf("failed to inspect agent container: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *DockerAgentController) Logs() (string, error) {
+	out, err := exec.Command("docker", "logs", c.containerName()).CombinedOutput()
+	return string(out), err
+}
+
+func (c *DockerAgentController) Pid() (int, error) {
+	out, err := exec.Command("docker", "inspect", "--format", "{{.State.Pid}}", c.containerName()).Output()
+	if err != nil {
+		return 0, err
+	}
+	return parsePid(string(out))
+}
+
+// isNoSuchContainer returns whether the docker output reports the container does not exist. docker inspect reports
+// "No such object" and docker rm "No such container".
+func isNoSuchContainer(out string) bool {
+	return strings.Contains(out, "No such container") || strings.Contains(out, "No such object")
+}
+
+func (c *DockerAgentController) containerName() string {
+	if c.ContainerName == "" {
+		return defaultContainerName
+	}
+	return c.ContainerName
+}
+
+func parsePid(out string) (int, error) {
+	pid, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, err
+	}
+	if pid == 0 {
+		return 0, errors.New("the agent is not running")
+	}
+	return pid, nil
+}
@@ -7,6 +7,7 @@ package common
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -133,6 +134,11 @@ func StartAgent(configOutputPath string, fatalOnFailure bool, ssm bool) error {
 }
 
 func StartAgentWithCommand(configOutputPath string, fatalOnFailure bool, ssm bool, agentStartCommand string) error {
+	return startAgent(context.Background(), configOutputPath, fatalOnFailure, ssm, agentStartCommand)
+}
+
+// startAgent kills the start command when the context is done, e.g when fetching the agent config hangs
+func startAgent(ctx context.Context, configOutputPath string, fatalOnFailure bool, ssm bool, agentStartCommand string) error {
 	path := "file:"
 	if ssm {
 		path = "ssm:"
@@ -140,7 +146,7 @@ func StartAgentWithCommand(configOutputPath string, fatalOnFailure bool, ssm boo
 	completedAgentStartCommand := agentStartCommand + path + configOutputPath
 	log.Printf("Starting agent with command %s", completedAgentStartCommand)
 	out, err := exec.
-		Command("bash", "-c", completedAgentStartCommand).
+		CommandContext(ctx, "bash", "-c", completedAgentStartCommand).
 		Output()
 
 	if err != nil && fatalOnFailure {
@@ -155,15 +161,22 @@ func StartAgentWithCommand(configOutputPath string, fatalOnFailure bool, ssm boo
 }
 
 func StopAgent() {
+	if err := stopAgent(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func stopAgent() error {
 	out, err := exec.
-		Command("bash", "-c", "sudo /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl -a stop").
+		Command("bash", "-c", "sudo "+agentCtlPath+" -a stop").
 		Output()
 
 	if err != nil {
-		log.Fatal(fmt.Sprint(err) + string(out))
+		return fmt.Errorf("%v%s", err, out)
 	}
 
 	log.Printf("Agent is stopped")
+	return nil
 }
 
 func ReadAgentLogfile(logfile string) string {
@@ -229,10 +242,24 @@ func RunCommands(commands []string) error {
 	return nil
 }
 
-func ReplaceLocalStackHostName(pathIn string) {
-	out, err := exec.Command("bash", "-c", "sed -i 's/localhost.localstack.cloud/'\"$LOCAL_STACK_HOST_NAME\"'/g' "+pathIn).Output()
-
+// CopyAgentConfig renders the agent config template and copies the result to the output path (see RenderAgentConfig)
+func CopyAgentConfig(templatePath string, outputPath string, variables AgentConfigVariables) error {
+	rendered, err := RenderAgentConfig(templatePath, variables)
 	if err != nil {
-		log.Fatal(fmt.Sprint(err) + string(out))
+		return err
+	}
+	renderedFile, err := os.CreateTemp("", "cwagent-config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(renderedFile.Name())
+	_, err = renderedFile.Write(rendered)
+	if closeErr := renderedFile.Close(); err == nil {
+		err = closeErr
 	}
+	if err != nil {
+		return err
+	}
+	CopyFile(renderedFile.Name(), outputPath)
+	return nil
 }
@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+// Builder builds an agent config in code, e.g
+//
+//	config, err := agentconfig.NewBuilder().
+//		RunAsUser("root").
+//		Namespace("MetricValueBenchmarkTest").
+//		AppendDimensions(map[string]string{"InstanceId": "${aws:InstanceId}"}).
+//		CollectMetrics("cpu", &agentconfig.Plugin{Measurement: agentconfig.Measurements("time_active")}).
+//		CollectLogFile(&agentconfig.LogFile{FilePath: "/tmp/test1.log", LogGroupName: "{instance_id}"}).
+//		Build()
+type Builder struct {
+	config Config
+}
+
+// NewBuilder returns a builder starting from an empty agent config
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// NewBuilderFrom returns a builder adding to the agent config. The sections of the agent config are modified in place.
+func NewBuilderFrom(config *Config) *Builder {
+	return &Builder{config: *config}
+}
+
+func (b *Builder) agent() *Agent {
+	if b.config.Agent == nil {
+		b.config.Agent = &Agent{}
+	}
+	return b.config.Agent
+}
+
+func (b *Builder) metrics() *Metrics {
+	if b.config.Metrics == nil {
+		b.config.Metrics = &Metrics{}
+	}
+	return b.config.Metrics
+}
+
+func (b *Builder) logsCollected() *LogsCollected {
+	if b.config.Logs == nil {
+		b.config.Logs = &Logs{}
+	}
+	if b.config.Logs.LogsCollected == nil {
+		b.config.Logs.LogsCollected = &LogsCollected{}
+	}
+	return b.config.Logs.LogsCollected
+}
+
+func (b *Builder) tracesCollected() *TracesCollected {
+	if b.config.Traces == nil {
+		b.config.Traces = &Traces{}
+	}
+	if b.config.Traces.TracesCollected == nil {
+		b.config.Traces.TracesCollected = &TracesCollected{}
+	}
+	return b.config.Traces.TracesCollected
+}
+
+// MetricsCollectionInterval sets agent.metrics_collection_interval in seconds
+func (b *Builder) MetricsCollectionInterval(seconds int) *Builder {
+	b.agent().MetricsCollectionInterval = seconds
+	return b
+}
+
+func (b *Builder) RunAsUser(user string) *Builder {
+	b.agent().RunAsUser = user
+	return b
+}
+
+func (b *Builder) Debug(debug bool) *Builder {
+	b.agent().Debug = debug
+	return b
+}
+
+func (b *Builder) Logfile(path string) *Builder {
+	b.agent().Logfile = &path
+	return b
+}
+
+func (b *Builder) Namespace(namespace string) *Builder {
+	b.metrics().Namespace = namespace
+	return b
+}
+
+// AppendDimensions adds the dimensions to metrics.append_dimensions
+func (b *Builder) AppendDimensions(dimensions map[string]string) *Builder {
+	metrics := b.metrics()
+	if metrics.AppendDimensions == nil {
+		metrics.AppendDimensions = make(map[string]string, len(dimensions))
+	}
+	for name, value := range dimensions {
+		metrics.AppendDimensions[name] = value
+	}
+	return b
+}
+
+// AggregationDimensions adds a dimension set to metrics.aggregation_dimensions
+func (b *Builder) AggregationDimensions(dimensions ...string) *Builder {
+	metrics := b.metrics()
+	metrics.AggregationDimensions = append(metrics.AggregationDimensions, dimensions)
+	return b
+}
+
+// CollectMetrics sets the plugin under metrics.metrics_collected
+func (b *Builder) CollectMetrics(name string, plugin *Plugin) *Builder {
+	metrics := b.metrics()
+	if metrics.MetricsCollected == nil {
+		metrics.MetricsCollected = make(map[string]*Plugin)
+	}
+	metrics.MetricsCollected[name] = plugin
+	return b
+}
+
+// CollectLogFile adds the log file to logs.logs_collected.files.collect_list
+func (b *Builder) CollectLogFile(logFile *LogFile) *Builder {
+	logsCollected := b.logsCollected()
+	if logsCollected.Files == nil {
+		logsCollected.Files = &Files{}
+	}
+	logsCollected.Files.CollectList = append(logsCollected.Files.CollectList, logFile)
+	return b
+}
+
+// CollectWindowsEvent adds the windows event to logs.logs_collected.windows_events.collect_list
+func (b *Builder) CollectWindowsEvent(windowsEvent *WindowsEvent) *Builder {
+	logsCollected := b.logsCollected()
+	if logsCollected.WindowsEvents == nil {
+		logsCollected.WindowsEvents = &WindowsEvents{}
+	}
+	logsCollected.WindowsEvents.CollectList = append(logsCollected.WindowsEvents.CollectList, windowsEvent)
+	return b
+}
+
+func (b *Builder) CollectXRay(xray *XRay) *Builder {
+	b.tracesCollected().XRay = xray
+	return b
+}
+
+func (b *Builder) CollectOTLP(otlp *OTLP) *Builder {
+	b.tracesCollected().OTLP = otlp
+	return b
+}
+
+// Build validates and returns the agent config
+func (b *Builder) Build() (*Config, error) {
+	config := b.config
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
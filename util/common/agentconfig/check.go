@@ -0,0 +1,218 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/jsonschema"
+	"go.uber.org/multierr"
+)
+
+// unknownKeyMessage is the error of the {"not": {}} schema the embedded schema rejects unknown keys with
+const unknownKeyMessage = "result was valid, ('not') expected invalid"
+
+var (
+	// defaultSchema is the subset of the agent config schema the Checker validates with unless given the schema of
+	// the agent (translator/config/schema.json in the amazon-cloudwatch-agent repo)
+	//go:embed schema.json
+	defaultSchema []byte
+	// sectionNames are the top level keys telling a JSON file is an agent config
+	sectionNames = []string{"agent", "metrics", "logs", "traces"}
+	// configDirectories are the directories of a test holding its agent configs. The test owning an agent config
+	// is the directory above.
+	configDirectories = map[string]struct{}{
+		"agent_configs":     {},
+		"resources":         {},
+		"eks_resources":     {},
+		"default_resources": {},
+		"testdata":          {},
+	}
+)
+
+// ConfigFile is an agent config found in the repo. Test is the directory of the test owning the agent config.
+type ConfigFile struct {
+	Path string
+	Test string
+	Data []byte
+}
+
+// Finding is a problem of an agent config reported by the Checker
+type Finding struct {
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return f.Path + ": " + f.Message
+}
+
+// FindConfigFiles walks root for the agent configs, the JSON files with an agent, metrics, logs or traces section.
+// The JSON files which do not parse are returned when they look like agent configs, so the Checker reports them.
+func FindConfigFiles(root string) ([]ConfigFile, error) {
+	var files []ConfigFile
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !isAgentConfig(data) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, ConfigFile{Path: path, Test: owningTest(rel), Data: data})
+		return nil
+	})
+	return files, err
+}
+
+func isAgentConfig(data []byte) bool {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return bytes.Contains(data, []byte(`"metrics_collected"`)) || bytes.Contains(data, []byte(`"logs_collected"`)) ||
+			bytes.Contains(data, []byte(`"traces_collected"`))
+	}
+	for _, name := range sectionNames {
+		if _, ok := sections[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func owningTest(rel string) string {
+	directories := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	for i, directory := range directories {
+		if _, ok := configDirectories[directory]; ok {
+			return strings.Join(directories[:i], "/")
+		}
+	}
+	return strings.Join(directories, "/")
+}
+
+// Checker validates agent configs against the agent config schema and the model Validate, then lints them against
+// each other
+type Checker struct {
+	schema *jsonschema.Schema
+}
+
+// NewChecker returns a Checker validating with the embedded schema
+func NewChecker() (*Checker, error) {
+	return NewCheckerWithSchema(defaultSchema)
+}
+
+// NewCheckerWithSchema returns a Checker validating with the JSON schema (e.g the schema of the agent)
+func NewCheckerWithSchema(schema []byte) (*Checker, error) {
+	rs := &jsonschema.Schema{}
+	if err := json.Unmarshal(schema, rs); err != nil {
+		return nil, fmt.Errorf("invalid agent config schema: %w", err)
+	}
+	return &Checker{schema: rs}, nil
+}
+
+// Check returns the findings of the agent configs sorted by path. Besides the schema and Validate, a log group
+// collected by the agent configs of different tests is reported, since the tests would read each other's logs.
+// Log groups named after the host (e.g {instance_id}) are unique to the test host and are not reported.
+func (c *Checker) Check(files []ConfigFile) []Finding {
+	var findings []Finding
+	logGroups := make(map[string][]ConfigFile)
+	for _, file := range files {
+		config, fileFindings := c.checkFile(file)
+		findings = append(findings, fileFindings...)
+		if config == nil {
+			continue
+		}
+		for _, name := range config.logGroupNames() {
+			if !strings.Contains(name, "{") {
+				logGroups[name] = append(logGroups[name], file)
+			}
+		}
+	}
+	for name, groupFiles := range logGroups {
+		for _, file := range groupFiles {
+			for _, other := range groupFiles {
+				if other.Test != file.Test {
+					findings = append(findings, Finding{
+						Path:    file.Path,
+						Message: fmt.Sprintf("log group %q is also collected by test %s (%s)", name, other.Test, other.Path),
+					})
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+	return findings
+}
+
+func (c *Checker) checkFile(file ConfigFile) (*Config, []Finding) {
+	var findings []Finding
+	var config Config
+	if err := json.Unmarshal(file.Data, &config); err != nil {
+		return nil, []Finding{{Path: file.Path, Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	keyErrors, err := c.schema.ValidateBytes(context.Background(), file.Data)
+	if err != nil {
+		findings = append(findings, Finding{Path: file.Path, Message: fmt.Sprintf("failed to validate with the schema: %v", err)})
+	}
+	for _, keyError := range keyErrors {
+		message := keyError.Message
+		if message == unknownKeyMessage {
+			message = "unknown key"
+		}
+		findings = append(findings, Finding{Path: file.Path, Message: fmt.Sprintf("%s: %s", keyError.PropertyPath, message)})
+	}
+	for _, err = range multierr.Errors(config.Validate()) {
+		findings = append(findings, Finding{Path: file.Path, Message: err.Error()})
+	}
+	return &config, findings
+}
+
+func (c *Config) logGroupNames() []string {
+	if c.Logs == nil || c.Logs.LogsCollected == nil {
+		return nil
+	}
+	var names []string
+	if files := c.Logs.LogsCollected.Files; files != nil {
+		for _, logFile := range files.CollectList {
+			if logFile.LogGroupName != "" {
+				names = append(names, logFile.LogGroupName)
+			}
+		}
+	}
+	if windowsEvents := c.Logs.LogsCollected.WindowsEvents; windowsEvents != nil {
+		for _, windowsEvent := range windowsEvents.CollectList {
+			if windowsEvent.LogGroupName != "" {
+				names = append(names, windowsEvent.LogGroupName)
+			}
+		}
+	}
+	return names
+}
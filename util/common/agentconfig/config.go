@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package agentconfig models the CloudWatchAgent JSON config, so tests and validators can build and read agent
+// configs without digging through generic maps. The sections only type the fields the tests use, every other
+// field is kept in Unknown and written back as it was.
+package agentconfig
+
+import (
+	"encoding/json"
+	"os"
+)
+
+type Config struct {
+	Agent   *Agent   `json:"agent,omitempty"`
+	Metrics *Metrics `json:"metrics,omitempty"`
+	Logs    *Logs    `json:"logs,omitempty"`
+	Traces  *Traces  `json:"traces,omitempty"`
+	Unknown Unknown  `json:"-"`
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type section Config
+	return unmarshalSection(data, (*section)(c), &c.Unknown)
+}
+
+func (c Config) MarshalJSON() ([]byte, error) {
+	type section Config
+	return marshalSection(section(c), c.Unknown)
+}
+
+type Agent struct {
+	MetricsCollectionInterval int    `json:"metrics_collection_interval,omitempty"`
+	Region                    string `json:"region,omitempty"`
+	RunAsUser                 string `json:"run_as_user,omitempty"`
+	Debug                     bool   `json:"debug,omitempty"`
+	// Logfile is a pointer since an empty logfile (log to stdout) is not the same as the default logfile
+	Logfile      *string `json:"logfile,omitempty"`
+	OmitHostname bool    `json:"omit_hostname,omitempty"`
+	Unknown      Unknown `json:"-"`
+}
+
+func (a *Agent) UnmarshalJSON(data []byte) error {
+	type section Agent
+	return unmarshalSection(data, (*section)(a), &a.Unknown)
+}
+
+func (a Agent) MarshalJSON() ([]byte, error) {
+	type section Agent
+	return marshalSection(section(a), a.Unknown)
+}
+
+// Parse parses and validates the agent config
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Load parses and validates the agent config at the path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Write validates the agent config and writes it to the path
+func (c *Config) Write(path string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LogFilePaths returns the paths of the log files the agent collects
+func (c *Config) LogFilePaths() []string {
+	if c.Logs == nil || c.Logs.LogsCollected == nil || c.Logs.LogsCollected.Files == nil {
+		return nil
+	}
+	var filePaths []string
+	for _, logFile := range c.Logs.LogsCollected.Files.CollectList {
+		filePaths = append(filePaths, logFile.FilePath)
+	}
+	return filePaths
+}
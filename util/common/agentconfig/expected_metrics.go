@@ -0,0 +1,262 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	hostDimension        = "host"
+	procstatProcessName  = "process_name"
+	dropAllOriginalValue = "*"
+	// defaultCollectionInterval is the metrics_collection_interval in seconds when the agent config sets none
+	defaultCollectionInterval = 60
+)
+
+var (
+	// metricNamePrefixes are the prefixes of the plugins not named after their metrics. The jmx metrics keep their
+	// OpenTelemetry names.
+	metricNamePrefixes = map[string]string{
+		"nvidia_gpu": "nvidia_smi",
+		"jmx":        "",
+	}
+	// tagDimensions are the dimensions the plugins tag their metrics with on linux, with unknown values
+	tagDimensions = map[string][]string{
+		"disk":   {"device", "fstype", "path"},
+		"diskio": {"name"},
+		"net":    {"interface"},
+	}
+	// procstatSelectors are the procstat keys selecting the processes, which are also the dimension of the metrics
+	procstatSelectors = []string{"exe", "pattern", "pid_file"}
+)
+
+// Dimension is an expected dimension. The value is nil when it is only known on the host (e.g InstanceId).
+type Dimension struct {
+	Name  string
+	Value *string
+}
+
+// ExpectedMetric is a metric the agent sends with each of its dimension sets
+type ExpectedMetric struct {
+	Name          string
+	DimensionSets [][]Dimension
+	// Unit is the unit of the measurement in the agent config, empty when the agent picks it
+	Unit string
+	// CollectionInterval is the metrics_collection_interval in seconds of the plugin, else of the metrics or agent
+	// section
+	CollectionInterval int
+}
+
+// ExpectedMetrics returns the metrics the agent config makes the agent send to CloudWatch for the plugins with a
+// measurement list, named with the agent naming rules on linux (<plugin>_<measurement> unless renamed). The
+// dimension sets are the original dimensions (host or append_dimensions, the plugin append_dimensions and the
+// plugin tags) and the aggregation_dimensions the original dimensions contain. Original dimensions of the metrics
+// in drop_original_metrics are left out. Metrics generated by the load (e.g statsd) cannot be derived.
+func (c *Config) ExpectedMetrics() []ExpectedMetric {
+	if c.Metrics == nil {
+		return nil
+	}
+	omitHost := len(c.Metrics.AppendDimensions) > 0 || (c.Agent != nil && c.Agent.OmitHostname)
+	var globalDimensions []Dimension
+	if !omitHost {
+		globalDimensions = append(globalDimensions, Dimension{Name: hostDimension})
+	}
+	for name := range c.Metrics.AppendDimensions {
+		// The global append_dimensions are EC2 metadata (e.g ${aws:InstanceId})
+		globalDimensions = append(globalDimensions, Dimension{Name: name})
+	}
+
+	var expectedMetrics []ExpectedMetric
+	for _, pluginName := range sortedPluginNames(c.Metrics.MetricsCollected) {
+		plugin := c.Metrics.MetricsCollected[pluginName]
+		if plugin == nil {
+			continue
+		}
+		instances := plugin.Instances
+		if instances == nil {
+			instances = []*Plugin{plugin}
+		}
+		for _, instance := range instances {
+			collectionInterval := c.collectionInterval(instance)
+			original := append(append([]Dimension{}, globalDimensions...), pluginDimensions(pluginName, instance)...)
+			dropped := dropOriginalMetrics(instance)
+			for _, measurement := range instance.Measurement {
+				name := metricName(pluginName, measurement)
+				drop := isDropped(dropped, measurement.Name, metricName(pluginName, Measurement{Name: measurement.Name}))
+				expectedMetrics = append(expectedMetrics, ExpectedMetric{
+					Name:               name,
+					DimensionSets:      dimensionSets(original, c.Metrics.AggregationDimensions, drop),
+					Unit:               measurement.Unit,
+					CollectionInterval: collectionInterval,
+				})
+			}
+		}
+	}
+	return expectedMetrics
+}
+
+// collectionInterval returns the metrics_collection_interval of the plugin, which defaults to the one of the metrics
+// section, then of the agent section
+func (c *Config) collectionInterval(plugin *Plugin) int {
+	switch {
+	case plugin.MetricsCollectionInterval > 0:
+		return plugin.MetricsCollectionInterval
+	case c.Metrics.MetricsCollectionInterval > 0:
+		return c.Metrics.MetricsCollectionInterval
+	case c.Agent != nil && c.Agent.MetricsCollectionInterval > 0:
+		return c.Agent.MetricsCollectionInterval
+	default:
+		return defaultCollectionInterval
+	}
+}
+
+// ExpectedMetricNames returns the names of the ExpectedMetrics
+func (c *Config) ExpectedMetricNames() []string {
+	var names []string
+	for _, expectedMetric := range c.ExpectedMetrics() {
+		names = append(names, expectedMetric.Name)
+	}
+	return names
+}
+
+func sortedPluginNames(plugins map[string]*Plugin) []string {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func metricName(pluginName string, measurement Measurement) string {
+	if measurement.Rename != "" {
+		return measurement.Rename
+	}
+	prefix, ok := metricNamePrefixes[pluginName]
+	if !ok {
+		prefix = pluginName
+	}
+	if prefix == "" || strings.HasPrefix(measurement.Name, prefix+"_") {
+		return measurement.Name
+	}
+	return prefix + "_" + measurement.Name
+}
+
+func pluginDimensions(pluginName string, plugin *Plugin) []Dimension {
+	var dimensions []Dimension
+	for name, value := range plugin.AppendDimensions {
+		value := value
+		dimensions = append(dimensions, Dimension{Name: name, Value: &value})
+	}
+	switch pluginName {
+	case "cpu":
+		// Without totalcpu, only the metrics of the single cores (cpu0, cpu1...) are sent
+		if !plugin.unknownBool("totalcpu", true) {
+			dimensions = append(dimensions, Dimension{Name: "cpu"})
+		} else {
+			total := "cpu-total"
+			dimensions = append(dimensions, Dimension{Name: "cpu", Value: &total})
+		}
+	case "procstat":
+		for _, selector := range procstatSelectors {
+			if value, ok := plugin.unknownString(selector); ok {
+				dimensions = append(dimensions, Dimension{Name: selector, Value: &value})
+			}
+		}
+		dimensions = append(dimensions, Dimension{Name: procstatProcessName})
+	default:
+		for _, name := range tagDimensions[pluginName] {
+			if name == "device" && plugin.unknownBool("drop_device", false) {
+				continue
+			}
+			dimensions = append(dimensions, Dimension{Name: name})
+		}
+	}
+	return dimensions
+}
+
+func dropOriginalMetrics(plugin *Plugin) map[string]struct{} {
+	var names []string
+	if raw, ok := plugin.Unknown["drop_original_metrics"]; ok {
+		json.Unmarshal(raw, &names)
+	}
+	dropped := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		dropped[name] = struct{}{}
+	}
+	return dropped
+}
+
+// isDropped returns whether drop_original_metrics has the measurement with or without the plugin prefix
+func isDropped(dropped map[string]struct{}, names ...string) bool {
+	for _, name := range append(names, dropAllOriginalValue) {
+		if _, ok := dropped[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dimensionSets returns the original dimensions and the aggregation dimensions they contain. An aggregation equal
+// to the original dimensions is the original metric.
+func dimensionSets(original []Dimension, aggregationDimensions [][]string, dropOriginal bool) [][]Dimension {
+	values := make(map[string]Dimension, len(original))
+	for _, dimension := range original {
+		values[dimension.Name] = dimension
+	}
+	var sets [][]Dimension
+	if !dropOriginal {
+		sets = append(sets, sortedDimensions(original))
+	}
+	for _, aggregation := range aggregationDimensions {
+		if !containsAll(values, aggregation) || len(aggregation) == len(original) {
+			continue
+		}
+		set := make([]Dimension, 0, len(aggregation))
+		for _, name := range aggregation {
+			set = append(set, values[name])
+		}
+		sets = append(sets, sortedDimensions(set))
+	}
+	return sets
+}
+
+func containsAll(values map[string]Dimension, names []string) bool {
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedDimensions(dimensions []Dimension) []Dimension {
+	sorted := append([]Dimension{}, dimensions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func (p *Plugin) unknownBool(name string, defaultValue bool) bool {
+	raw, ok := p.Unknown[name]
+	if !ok {
+		return defaultValue
+	}
+	value := defaultValue
+	json.Unmarshal(raw, &value)
+	return value
+}
+
+func (p *Plugin) unknownString(name string) (string, bool) {
+	raw, ok := p.Unknown[name]
+	if !ok {
+		return "", false
+	}
+	var value string
+	return value, json.Unmarshal(raw, &value) == nil
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Unknown keeps the fields of a config section the model does not type, so an agent config is written back
+// without losing them
+type Unknown map[string]json.RawMessage
+
+// unmarshalSection unmarshals the typed fields into section, which has to be a pointer to a type without the
+// UnmarshalJSON method, and the remaining fields into unknown
+func unmarshalSection(data []byte, section interface{}, unknown *Unknown) error {
+	if err := json.Unmarshal(data, section); err != nil {
+		return err
+	}
+	var fields Unknown
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range jsonFieldNames(reflect.TypeOf(section).Elem()) {
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+	*unknown = fields
+	return nil
+}
+
+// marshalSection marshals the typed fields of section, which has to be a type without the MarshalJSON method,
+// together with the unknown fields
+func marshalSection(section interface{}, unknown Unknown) ([]byte, error) {
+	data, err := json.Marshal(section)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range unknown {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
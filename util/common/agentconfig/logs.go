@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+type Logs struct {
+	LogsCollected *LogsCollected `json:"logs_collected,omitempty"`
+	// MetricsCollected has the plugins sending metrics as logs (e.g emf and prometheus)
+	MetricsCollected   map[string]*Plugin `json:"metrics_collected,omitempty"`
+	LogStreamName      string             `json:"log_stream_name,omitempty"`
+	ForceFlushInterval int                `json:"force_flush_interval,omitempty"`
+	EndpointOverride   string             `json:"endpoint_override,omitempty"`
+	Unknown            Unknown            `json:"-"`
+}
+
+func (l *Logs) UnmarshalJSON(data []byte) error {
+	type section Logs
+	return unmarshalSection(data, (*section)(l), &l.Unknown)
+}
+
+func (l Logs) MarshalJSON() ([]byte, error) {
+	type section Logs
+	return marshalSection(section(l), l.Unknown)
+}
+
+type LogsCollected struct {
+	Files         *Files         `json:"files,omitempty"`
+	WindowsEvents *WindowsEvents `json:"windows_events,omitempty"`
+	Unknown       Unknown        `json:"-"`
+}
+
+func (l *LogsCollected) UnmarshalJSON(data []byte) error {
+	type section LogsCollected
+	return unmarshalSection(data, (*section)(l), &l.Unknown)
+}
+
+func (l LogsCollected) MarshalJSON() ([]byte, error) {
+	type section LogsCollected
+	return marshalSection(section(l), l.Unknown)
+}
+
+type Files struct {
+	CollectList []*LogFile `json:"collect_list"`
+	Unknown     Unknown    `json:"-"`
+}
+
+func (f *Files) UnmarshalJSON(data []byte) error {
+	type section Files
+	return unmarshalSection(data, (*section)(f), &f.Unknown)
+}
+
+func (f Files) MarshalJSON() ([]byte, error) {
+	type section Files
+	return marshalSection(section(f), f.Unknown)
+}
+
+type LogFile struct {
+	FilePath              string  `json:"file_path"`
+	LogGroupName          string  `json:"log_group_name,omitempty"`
+	LogStreamName         string  `json:"log_stream_name,omitempty"`
+	RetentionInDays       int     `json:"retention_in_days,omitempty"`
+	Timezone              string  `json:"timezone,omitempty"`
+	TimestampFormat       string  `json:"timestamp_format,omitempty"`
+	MultiLineStartPattern string  `json:"multi_line_start_pattern,omitempty"`
+	Encoding              string  `json:"encoding,omitempty"`
+	Unknown               Unknown `json:"-"`
+}
+
+func (l *LogFile) UnmarshalJSON(data []byte) error {
+	type section LogFile
+	return unmarshalSection(data, (*section)(l), &l.Unknown)
+}
+
+func (l LogFile) MarshalJSON() ([]byte, error) {
+	type section LogFile
+	return marshalSection(section(l), l.Unknown)
+}
+
+type WindowsEvents struct {
+	CollectList []*WindowsEvent `json:"collect_list"`
+	Unknown     Unknown         `json:"-"`
+}
+
+func (w *WindowsEvents) UnmarshalJSON(data []byte) error {
+	type section WindowsEvents
+	return unmarshalSection(data, (*section)(w), &w.Unknown)
+}
+
+func (w WindowsEvents) MarshalJSON() ([]byte, error) {
+	type section WindowsEvents
+	return marshalSection(section(w), w.Unknown)
+}
+
+type WindowsEvent struct {
+	EventName       string   `json:"event_name"`
+	EventLevels     []string `json:"event_levels,omitempty"`
+	EventFormat     string   `json:"event_format,omitempty"`
+	LogGroupName    string   `json:"log_group_name,omitempty"`
+	LogStreamName   string   `json:"log_stream_name,omitempty"`
+	RetentionInDays int      `json:"retention_in_days,omitempty"`
+	Unknown         Unknown  `json:"-"`
+}
+
+func (w *WindowsEvent) UnmarshalJSON(data []byte) error {
+	type section WindowsEvent
+	return unmarshalSection(data, (*section)(w), &w.Unknown)
+}
+
+func (w WindowsEvent) MarshalJSON() ([]byte, error) {
+	type section WindowsEvent
+	return marshalSection(section(w), w.Unknown)
+}
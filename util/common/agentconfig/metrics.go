@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+type Metrics struct {
+	Namespace                 string             `json:"namespace,omitempty"`
+	MetricsCollectionInterval int                `json:"metrics_collection_interval,omitempty"`
+	ForceFlushInterval        int                `json:"force_flush_interval,omitempty"`
+	AppendDimensions          map[string]string  `json:"append_dimensions,omitempty"`
+	AggregationDimensions     [][]string         `json:"aggregation_dimensions,omitempty"`
+	MetricsCollected          map[string]*Plugin `json:"metrics_collected,omitempty"`
+	MetricsDestinations       map[string]Unknown `json:"metrics_destinations,omitempty"`
+	Unknown                   Unknown            `json:"-"`
+}
+
+func (m *Metrics) UnmarshalJSON(data []byte) error {
+	type section Metrics
+	return unmarshalSection(data, (*section)(m), &m.Unknown)
+}
+
+func (m Metrics) MarshalJSON() ([]byte, error) {
+	type section Metrics
+	return marshalSection(section(m), m.Unknown)
+}
+
+// Plugin is a plugin under metrics_collected. The plugins configured with a list (e.g procstat) have one Plugin
+// per list entry in Instances.
+type Plugin struct {
+	Measurement               []Measurement     `json:"measurement,omitempty"`
+	Resources                 []string          `json:"resources,omitempty"`
+	MetricsCollectionInterval int               `json:"metrics_collection_interval,omitempty"`
+	AppendDimensions          map[string]string `json:"append_dimensions,omitempty"`
+	Instances                 []*Plugin         `json:"-"`
+	Unknown                   Unknown           `json:"-"`
+}
+
+func (p *Plugin) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &p.Instances)
+	}
+	type section Plugin
+	return unmarshalSection(data, (*section)(p), &p.Unknown)
+}
+
+func (p Plugin) MarshalJSON() ([]byte, error) {
+	if p.Instances != nil {
+		return json.Marshal(p.Instances)
+	}
+	type section Plugin
+	return marshalSection(section(p), p.Unknown)
+}
+
+// Measurement is a measurement of a plugin, written as its name unless it is renamed or has a unit
+type Measurement struct {
+	Name   string `json:"name"`
+	Rename string `json:"rename,omitempty"`
+	Unit   string `json:"unit,omitempty"`
+}
+
+// Measurements returns the measurements with the names
+func Measurements(names ...string) []Measurement {
+	measurements := make([]Measurement, 0, len(names))
+	for _, name := range names {
+		measurements = append(measurements, Measurement{Name: name})
+	}
+	return measurements
+}
+
+func (m *Measurement) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '"' {
+		*m = Measurement{}
+		return json.Unmarshal(data, &m.Name)
+	}
+	type measurement Measurement
+	return json.Unmarshal(data, (*measurement)(m))
+}
+
+func (m Measurement) MarshalJSON() ([]byte, error) {
+	if m.Rename == "" && m.Unit == "" {
+		return json.Marshal(m.Name)
+	}
+	type measurement Measurement
+	return json.Marshal(measurement(m))
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+type Traces struct {
+	TracesCollected  *TracesCollected `json:"traces_collected,omitempty"`
+	EndpointOverride string           `json:"endpoint_override,omitempty"`
+	Unknown          Unknown          `json:"-"`
+}
+
+func (t *Traces) UnmarshalJSON(data []byte) error {
+	type section Traces
+	return unmarshalSection(data, (*section)(t), &t.Unknown)
+}
+
+func (t Traces) MarshalJSON() ([]byte, error) {
+	type section Traces
+	return marshalSection(section(t), t.Unknown)
+}
+
+type TracesCollected struct {
+	XRay    *XRay   `json:"xray,omitempty"`
+	OTLP    *OTLP   `json:"otlp,omitempty"`
+	Unknown Unknown `json:"-"`
+}
+
+func (t *TracesCollected) UnmarshalJSON(data []byte) error {
+	type section TracesCollected
+	return unmarshalSection(data, (*section)(t), &t.Unknown)
+}
+
+func (t TracesCollected) MarshalJSON() ([]byte, error) {
+	type section TracesCollected
+	return marshalSection(section(t), t.Unknown)
+}
+
+type XRay struct {
+	BindAddress string  `json:"bind_address,omitempty"`
+	Unknown     Unknown `json:"-"`
+}
+
+func (x *XRay) UnmarshalJSON(data []byte) error {
+	type section XRay
+	return unmarshalSection(data, (*section)(x), &x.Unknown)
+}
+
+func (x XRay) MarshalJSON() ([]byte, error) {
+	type section XRay
+	return marshalSection(section(x), x.Unknown)
+}
+
+type OTLP struct {
+	GRPCEndpoint string  `json:"grpc_endpoint,omitempty"`
+	HTTPEndpoint string  `json:"http_endpoint,omitempty"`
+	Unknown      Unknown `json:"-"`
+}
+
+func (o *OTLP) UnmarshalJSON(data []byte) error {
+	type section OTLP
+	return unmarshalSection(data, (*section)(o), &o.Unknown)
+}
+
+func (o OTLP) MarshalJSON() ([]byte, error) {
+	type section OTLP
+	return marshalSection(section(o), o.Unknown)
+}
@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agentconfig
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	windowsEventLevels = map[string]struct{}{
+		"VERBOSE":     {},
+		"INFORMATION": {},
+		"WARNING":     {},
+		"ERROR":       {},
+		"CRITICAL":    {},
+	}
+	windowsEventFormats = map[string]struct{}{
+		"":     {},
+		"xml":  {},
+		"text": {},
+	}
+	// retentionInDays are the retention periods CloudWatch Logs accepts besides 0 (never expire)
+	retentionInDays = map[int]struct{}{
+		1: {}, 3: {}, 5: {}, 7: {}, 14: {}, 30: {}, 60: {}, 90: {}, 120: {}, 150: {}, 180: {}, 365: {}, 400: {},
+		545: {}, 731: {}, 1096: {}, 1827: {}, 2192: {}, 2557: {}, 2922: {}, 3288: {}, 3653: {},
+	}
+)
+
+// Validate checks the agent config for the mistakes the agent would reject or silently ignore (e.g a log file
+// without file_path). It does not replace the config translator schema validation.
+func (c *Config) Validate() error {
+	var err error
+	if c.Agent != nil && c.Agent.MetricsCollectionInterval < 0 {
+		err = multierr.Append(err, fmt.Errorf("agent.metrics_collection_interval %d is negative", c.Agent.MetricsCollectionInterval))
+	}
+	if c.Metrics != nil {
+		err = multierr.Append(err, c.Metrics.validate())
+	}
+	if c.Logs != nil {
+		err = multierr.Append(err, c.Logs.validate())
+	}
+	if c.Traces != nil {
+		err = multierr.Append(err, c.Traces.validate())
+	}
+	return err
+}
+
+func (m *Metrics) validate() error {
+	var err error
+	if m.MetricsCollectionInterval < 0 {
+		err = multierr.Append(err, fmt.Errorf("metrics.metrics_collection_interval %d is negative", m.MetricsCollectionInterval))
+	}
+	for i, dimensions := range m.AggregationDimensions {
+		for _, dimension := range dimensions {
+			if dimension == "" {
+				err = multierr.Append(err, fmt.Errorf("metrics.aggregation_dimensions[%d] has an empty dimension", i))
+			}
+		}
+	}
+	return multierr.Append(err, validatePlugins("metrics.metrics_collected", m.MetricsCollected))
+}
+
+func validatePlugins(path string, plugins map[string]*Plugin) error {
+	var err error
+	for name, plugin := range plugins {
+		if plugin == nil {
+			err = multierr.Append(err, fmt.Errorf("%s.%s is null", path, name))
+			continue
+		}
+		instances := plugin.Instances
+		if instances == nil {
+			instances = []*Plugin{plugin}
+		}
+		for _, instance := range instances {
+			if instance.MetricsCollectionInterval < 0 {
+				err = multierr.Append(err, fmt.Errorf("%s.%s.metrics_collection_interval %d is negative", path, name, instance.MetricsCollectionInterval))
+			}
+			for _, measurement := range instance.Measurement {
+				if measurement.Name == "" {
+					err = multierr.Append(err, fmt.Errorf("%s.%s has a measurement without a name", path, name))
+				}
+			}
+		}
+	}
+	return err
+}
+
+func (l *Logs) validate() error {
+	err := validatePlugins("logs.metrics_collected", l.MetricsCollected)
+	if l.LogsCollected == nil {
+		if l.MetricsCollected == nil {
+			err = multierr.Append(err, errors.New("logs section has no logs_collected or metrics_collected"))
+		}
+		return err
+	}
+	if files := l.LogsCollected.Files; files != nil {
+		for i, logFile := range files.CollectList {
+			path := fmt.Sprintf("logs.logs_collected.files.collect_list[%d]", i)
+			if logFile.FilePath == "" {
+				err = multierr.Append(err, fmt.Errorf("%s has no file_path", path))
+			}
+			err = multierr.Append(err, validateRetention(path, logFile.RetentionInDays))
+		}
+	}
+	if windowsEvents := l.LogsCollected.WindowsEvents; windowsEvents != nil {
+		for i, windowsEvent := range windowsEvents.CollectList {
+			path := fmt.Sprintf("logs.logs_collected.windows_events.collect_list[%d]", i)
+			if windowsEvent.EventName == "" {
+				err = multierr.Append(err, fmt.Errorf("%s has no event_name", path))
+			}
+			for _, level := range windowsEvent.EventLevels {
+				if _, ok := windowsEventLevels[level]; !ok {
+					err = multierr.Append(err, fmt.Errorf("%s has invalid event level %q", path, level))
+				}
+			}
+			if _, ok := windowsEventFormats[windowsEvent.EventFormat]; !ok {
+				err = multierr.Append(err, fmt.Errorf("%s has invalid event_format %q", path, windowsEvent.EventFormat))
+			}
+			err = multierr.Append(err, validateRetention(path, windowsEvent.RetentionInDays))
+		}
+	}
+	return err
+}
+
+func validateRetention(path string, days int) error {
+	if days == 0 {
+		return nil
+	}
+	if _, ok := retentionInDays[days]; !ok {
+		return fmt.Errorf("%s has invalid retention_in_days %d", path, days)
+	}
+	return nil
+}
+
+func (t *Traces) validate() error {
+	if t.TracesCollected == nil || (t.TracesCollected.XRay == nil && t.TracesCollected.OTLP == nil && len(t.TracesCollected.Unknown) == 0) {
+		return errors.New("traces section has no traces_collected")
+	}
+	return nil
+}
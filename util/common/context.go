@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// Sleep pauses for the duration and returns the context error instead when the context is done first
+func Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunConcurrently runs the functions in their own go routine, waits for all of them to return and combines
+// their errors
+func RunConcurrently(fns ...func() error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		multiErr error
+	)
+	for _, fn := range fns {
+		wg.Add(1)
+		go func(fn func() error) {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				multiErr = multierr.Append(multiErr, err)
+				mu.Unlock()
+			}
+		}(fn)
+	}
+	wg.Wait()
+	return multiErr
+}
@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/environment"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const s3KeyPrefix = "diagnostics/"
+
+var unsafeNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// Agent reports the status and log of the agent under test (e.g the common.AgentController which started it)
+type Agent interface {
+	Status() (string, error)
+	Logs() (string, error)
+}
+
+// Options describes where the diagnostics bundle of a failed test goes and what it contains besides the agent
+// diagnostics (agent log, agent configs, agent status and service logs)
+type Options struct {
+	// Name identifies the failed test in the bundle file name
+	Name      string
+	Directory string
+	// Agent is optional. The status and log of the agent installed with the package are collected when it is not set.
+	Agent Agent
+	// AgentConfigPath is the agent config the agent was started with, common.ConfigOutputPath when it is not set
+	AgentConfigPath string
+	// Bucket is optional. The bundle is uploaded under the diagnostics/ prefix when it is set.
+	Bucket string
+	// MetaData is optional and added as metadata.json
+	MetaData *environment.MetaData
+	// Files maps the name in the bundle to the local path of extra files (e.g the validator config)
+	Files map[string]string
+}
+
+// Bundle is the content of a diagnostics tarball. Failing to collect an entry is recorded in the bundle instead
+// of failing the collection, since the bundle is most useful when the host is in a bad state.
+type Bundle struct {
+	entries map[string][]byte
+}
+
+func NewBundle() *Bundle {
+	return &Bundle{entries: map[string][]byte{}}
+}
+
+// AddContent adds an entry with the content
+func (b *Bundle) AddContent(name string, content []byte) {
+	b.entries[name] = content
+}
+
+// AddFile adds an entry with the content of the local file or the reason it could not be read
+func (b *Bundle) AddFile(name, path string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		b.AddContent(name+".error", []byte(err.Error()))
+		return
+	}
+	b.AddContent(name, content)
+}
+
+// AddCommand adds an entry with the output of the command and its error if any
+func (b *Bundle) AddCommand(name, cmd string) {
+	out, err := common.RunCommand(cmd)
+	content := fmt.Sprintf("$ %s\n%s", cmd, out)
+	if err != nil {
+		content += fmt.Sprintf("\nerror: %v\n", err)
+	}
+	b.AddContent(name, []byte(content))
+}
+
+// AddOutput adds an entry with the output of the function (e.g Agent.Logs) and its error if any
+func (b *Bundle) AddOutput(name string, output func() (string, error)) {
+	out, err := output()
+	if err != nil {
+		out += fmt.Sprintf("\nerror: %v\n", err)
+	}
+	b.AddContent(name, []byte(out))
+}
+
+// AddMetaData adds the environment metadata the tests ran with as metadata.json
+func (b *Bundle) AddMetaData(metaData *environment.MetaData) {
+	content, err := json.MarshalIndent(metaData, "", "  ")
+	if err != nil {
+		b.AddContent("metadata.json.error", []byte(err.Error()))
+		return
+	}
+	b.AddContent("metadata.json", content)
+}
+
+// Write writes the bundle as a gzipped tarball to the path
+func (b *Bundle) Write(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	names := make([]string, 0, len(b.entries))
+	for name := range b.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content := b.entries[name]
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err = tarWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Collect writes the diagnostics bundle to the directory as <name>-<timestamp>.tar.gz and uploads it when a bucket
+// is set. It returns the S3 URI of the uploaded bundle or the local path otherwise, so test results can link it.
+func Collect(options Options) (string, error) {
+	bundle := NewBundle()
+	addAgentDiagnostics(bundle, options)
+	if options.MetaData != nil {
+		bundle.AddMetaData(options.MetaData)
+	}
+	for name, path := range options.Files {
+		bundle.AddFile(name, path)
+	}
+
+	if err := os.MkdirAll(options.Directory, 0755); err != nil {
+		return "", err
+	}
+	fileName := fmt.Sprintf("%s-%s.tar.gz", unsafeNameCharacters.ReplaceAllString(options.Name, "_"), time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(options.Directory, fileName)
+	if err := bundle.Write(path); err != nil {
+		return "", err
+	}
+	log.Printf("Wrote diagnostics bundle %s", path)
+
+	if options.Bucket == "" {
+		return path, nil
+	}
+	key := s3KeyPrefix + fileName
+	if err := awsservice.UploadFile(options.Bucket, key, path); err != nil {
+		return path, err
+	}
+	return fmt.Sprintf("s3://%s/%s", options.Bucket, key), nil
+}
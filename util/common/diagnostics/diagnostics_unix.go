@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package diagnostics
+
+import (
+	"os"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const (
+	agentCtl          = "sudo /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-ctl"
+	agentEtcDirectory = "/opt/aws/amazon-cloudwatch-agent/etc/"
+	journalLines      = "500"
+)
+
+func addAgentDiagnostics(bundle *Bundle, options Options) {
+	agentConfigPath := options.AgentConfigPath
+	if agentConfigPath == "" {
+		agentConfigPath = common.ConfigOutputPath
+	}
+	bundle.AddFile("config.json", agentConfigPath)
+
+	if options.Agent != nil {
+		bundle.AddOutput("amazon-cloudwatch-agent.log", options.Agent.Logs)
+		bundle.AddOutput("status.txt", options.Agent.Status)
+		// The agent started by the binary or docker controllers is not installed with the package
+		if _, installed := options.Agent.(*common.CtlAgentController); !installed {
+			return
+		}
+	} else {
+		// ReadAgentLogfile exits on missing files, e.g when the agent never started
+		if _, err := os.Stat(common.AgentLogFile); err == nil {
+			bundle.AddContent("amazon-cloudwatch-agent.log", []byte(common.ReadAgentLogfile(common.AgentLogFile)))
+		} else {
+			bundle.AddContent("amazon-cloudwatch-agent.log.error", []byte(err.Error()))
+		}
+		bundle.AddCommand("status.txt", agentCtl+" -a status")
+	}
+	bundle.AddFile("amazon-cloudwatch-agent.toml", agentEtcDirectory+"amazon-cloudwatch-agent.toml")
+	bundle.AddFile("amazon-cloudwatch-agent.yaml", agentEtcDirectory+"amazon-cloudwatch-agent.yaml")
+	bundle.AddFile("common-config.toml", agentEtcDirectory+"common-config.toml")
+	bundle.AddCommand("amazon-cloudwatch-agent.d.txt", "sudo ls -l "+agentEtcDirectory+"amazon-cloudwatch-agent.d")
+	bundle.AddCommand("journal.txt", "sudo journalctl -u amazon-cloudwatch-agent --no-pager -n "+journalLines)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+
+package diagnostics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+)
+
+const (
+	agentCtl           = "& \"C:\\Program Files\\Amazon\\AmazonCloudWatchAgent\\amazon-cloudwatch-agent-ctl.ps1\""
+	agentDataDirectory = "C:\\ProgramData\\Amazon\\AmazonCloudWatchAgent\\"
+	eventLogEntries    = "500"
+)
+
+func addAgentDiagnostics(bundle *Bundle, options Options) {
+	agentConfigPath := options.AgentConfigPath
+	if agentConfigPath == "" {
+		agentConfigPath = common.ConfigOutputPath
+	}
+	bundle.AddFile("config.json", agentConfigPath)
+	bundle.AddFile("amazon-cloudwatch-agent.log", common.AgentLogFile)
+	bundle.AddFile("amazon-cloudwatch-agent.toml", agentDataDirectory+"amazon-cloudwatch-agent.toml")
+	bundle.AddFile("amazon-cloudwatch-agent.yaml", agentDataDirectory+"amazon-cloudwatch-agent.yaml")
+	bundle.AddFile("common-config.toml", agentDataDirectory+"common-config.toml")
+	bundle.AddCommand("status.txt", agentCtl+" -a status")
+	bundle.AddCommand("eventlog.txt", "Get-EventLog -LogName Application -Source AmazonCloudWatchAgent -Newest "+eventLogEntries+" | Format-List")
+}
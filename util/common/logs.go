@@ -4,29 +4,38 @@
 package common
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/multierr"
 
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/agentconfig"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/logs/fluent"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/logs/integrity"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/models"
 )
 
 const logLine = "# %d - This is a log line. \n"
 
-func GenerateLogs(configFilePath string, duration time.Duration, sendingInterval time.Duration, logLinesPerMinute int, validationLog []models.LogValidation) error {
+// logTracker numbers the log lines written by the validator when log_integrity is enabled so the delivered log
+// events can be checked
+var logTracker = integrity.NewTracker(uuid.NewString())
+
+func GenerateLogs(ctx context.Context, configFilePath string, duration time.Duration, sendingInterval time.Duration, logLinesPerMinute int, validationLog []models.LogValidation) error {
 	var multiErr error
-	if err := StartLogWrite(configFilePath, duration, sendingInterval, logLinesPerMinute); err != nil {
+	// The windows events are created before writing the logs for the whole duration
+	if err := GenerateWindowsEvents(validationLog); err != nil {
 		multiErr = multierr.Append(multiErr, err)
 	}
-	if err := GenerateWindowsEvents(validationLog); err != nil {
+	if err := StartLogWrite(ctx, configFilePath, duration, sendingInterval, logLinesPerMinute, nil); err != nil {
 		multiErr = multierr.Append(multiErr, err)
 	}
 	return multiErr
@@ -57,30 +66,53 @@ func CreateWindowsEvent(eventLogName string, eventLogLevel string, msg string) e
 	return nil
 }
 
-// StartLogWrite starts go routines to write logs to each of the logs that are monitored by CW Agent according to
-// the config provided
-func StartLogWrite(configFilePath string, duration time.Duration, sendingInterval time.Duration, logLinesPerMinute int) error {
-	var multiErr error
-
+// StartLogWrite writes logs to each of the logs that are monitored by CW Agent according to the config provided
+// in their own go routine, and returns their errors once all of them are done. The log lines are numbered by the
+// tracker when it is not nil.
+func StartLogWrite(ctx context.Context, configFilePath string, duration time.Duration, sendingInterval time.Duration, logLinesPerMinute int, tracker *integrity.Tracker) error {
 	logPaths, err := getLogFilePaths(configFilePath)
 	if err != nil {
 		return err
 	}
 
-	for _, logPath := range logPaths {
-		go func(logPath string) {
-			if err := writeToLogs(logPath, duration, sendingInterval, logLinesPerMinute); err != nil {
-				multiErr = multierr.Append(multiErr, err)
-			}
-		}(logPath)
+	writers := make([]func() error, len(logPaths))
+	for i, logPath := range logPaths {
+		logPath := logPath
+		writers[i] = func() error {
+			return writeToLogs(ctx, logPath, duration, sendingInterval, logLinesPerMinute, tracker)
+		}
 	}
+	return RunConcurrently(writers...)
+}
 
-	return multiErr
+// StartFluentForward sends log events to a fluent forward input (e.g Fluent Bit, Fluentd) with the forward
+// protocol mode provided until the duration has elapsed
+func StartFluentForward(ctx context.Context, forwardConfig models.FluentForwardConfig, duration time.Duration, sendingInterval time.Duration, logLinesPerMinute int) error {
+	generator, err := fluent.NewGenerator(fluent.GeneratorConfig{
+		Address:           forwardConfig.Address,
+		Tag:               forwardConfig.Tag,
+		Mode:              forwardConfig.Mode,
+		RequireAck:        forwardConfig.RequireAck,
+		BatchSize:         forwardConfig.BatchSize,
+		Interval:          sendingInterval,
+		EventsPerInterval: logLinesPerMinute,
+	})
+	if err != nil {
+		return err
+	}
+
+	return generator.SendEvents(ctx, duration)
+}
+
+// LogIntegrityTracker returns the tracker numbering the log lines written for log_integrity. Each log file is
+// identified by its file name (e.g test1.log) which is also the log stream name in the generated agent configs.
+func LogIntegrityTracker() *integrity.Tracker {
+	return logTracker
 }
 
 // writeToLogs opens a file at the specified file path and writes the specified number of lines per second (tps)
 // for the specified duration
-func writeToLogs(filePath string, duration, sendingInterval time.Duration, logLinesPerMinute int) error {
+func writeToLogs(ctx context.Context, filePath string, duration, sendingInterval time.Duration, logLinesPerMinute int, tracker *integrity.Tracker) error {
 	f, err := os.Create(filePath)
 	if err != nil {
 		return err
@@ -88,13 +120,21 @@ func writeToLogs(filePath string, duration, sendingInterval time.Duration, logLi
 	defer f.Close()
 	defer os.Remove(filePath)
 
+	fileID := filepath.Base(filePath)
+	nextLine := func(i int) string {
+		if tracker == nil {
+			return fmt.Sprintf(logLine, i)
+		}
+		return tracker.NextLine(fileID, i)
+	}
+
 	ticker := time.NewTicker(sendingInterval)
 	defer ticker.Stop()
 	endTimeout := time.After(duration)
 
 	// Sending the logs within the first minute before the ticker kicks in the next minute
 	for i := 0; i < logLinesPerMinute; i++ {
-		_, err := f.WriteString(fmt.Sprintf(logLine, i))
+		_, err := f.WriteString(nextLine(i))
 		if err != nil {
 			return err
 		}
@@ -104,10 +144,12 @@ func writeToLogs(filePath string, duration, sendingInterval time.Duration, logLi
 		select {
 		case <-ticker.C:
 			for i := 0; i < logLinesPerMinute; i++ {
-				f.WriteString(fmt.Sprintf(logLine, i))
+				f.WriteString(nextLine(i))
 			}
 		case <-endTimeout:
 			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 }
@@ -115,68 +157,34 @@ func writeToLogs(filePath string, duration, sendingInterval time.Duration, logLi
 // getLogFilePaths parses the cloudwatch agent config at the specified path and returns a list of the log files that the
 // agent will monitor when using that config file
 func getLogFilePaths(configPath string) ([]string, error) {
-	file, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
-
-	var cfgFileData map[string]interface{}
-	err = json.Unmarshal(file, &cfgFileData)
+	config, err := agentconfig.Load(configPath)
 	if err != nil {
 		return nil, err
 	}
-
-	logFiles := cfgFileData["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"].([]interface{})
-	var filePaths []string
-	for _, process := range logFiles {
-		filePaths = append(filePaths, process.(map[string]interface{})["file_path"].(string))
-	}
-
-	return filePaths, nil
+	return config.LogFilePaths(), nil
 }
 
 // GenerateLogConfig takes the number of logs to be monitored and applies it to the supplied config,
 // It writes logs to be monitored of the form /tmp/testNUM.log where NUM is from 1 to number of logs requested to
-// the supplied configuration
-// DEFAULT CONFIG MUST BE SUPPLIED WITH AT LEAST ONE LOG BEING MONITORED
-// (log being monitored will be overwritten - it is needed for json structure)
-// returns the path of the config generated and a list of log stream names
+// the supplied configuration. The log files already monitored by the supplied configuration are replaced.
 func GenerateLogConfig(numberMonitoredLogs int, filePath string) error {
 	if numberMonitoredLogs == 0 || filePath == "" {
 		return errors.New("number of monitored logs or file path is empty")
 	}
 
-	type LogInfo struct {
-		FilePath        string `json:"file_path"`
-		LogGroupName    string `json:"log_group_name"`
-		LogStreamName   string `json:"log_stream_name"`
-		RetentionInDays int    `json:"retention_in_days"`
-		Timezone        string `json:"timezone"`
-	}
-
-	var cfgFileData map[string]interface{}
 	// For metrics and traces, we will keep the default config while log will be appended dynamically
-	file, err := os.OpenFile(filePath, os.O_RDWR, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	fileBytes, err := io.ReadAll(file)
+	config, err := agentconfig.Load(filePath)
 	if err != nil {
 		return err
 	}
-
-	err = json.Unmarshal(fileBytes, &cfgFileData)
-	if err != nil {
-		return err
+	if config.Logs != nil && config.Logs.LogsCollected != nil && config.Logs.LogsCollected.Files != nil {
+		config.Logs.LogsCollected.Files.CollectList = nil
 	}
 
-	var logFiles []LogInfo
+	builder := agentconfig.NewBuilderFrom(config)
 	tempFolder := getTempFolder()
-
 	for i := 0; i < numberMonitoredLogs; i++ {
-		logFiles = append(logFiles, LogInfo{
+		builder.CollectLogFile(&agentconfig.LogFile{
 			FilePath:        fmt.Sprintf("%s/test%d.log", tempFolder, i+1),
 			LogGroupName:    "{instance_id}",
 			LogStreamName:   fmt.Sprintf("test%d.log", i+1),
@@ -184,22 +192,12 @@ func GenerateLogConfig(numberMonitoredLogs int, filePath string) error {
 			Timezone:        "UTC",
 		})
 	}
-
-	log.Printf("Writing config file with %d logs to %v", numberMonitoredLogs, filePath)
-
-	cfgFileData["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"] = logFiles
-
-	finalConfig, err := json.MarshalIndent(cfgFileData, "", " ")
-	if err != nil {
+	if config, err = builder.Build(); err != nil {
 		return err
 	}
 
-	err = os.WriteFile(filePath, finalConfig, 0644)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	log.Printf("Writing config file with %d logs to %v", numberMonitoredLogs, filePath)
+	return config.Write(filePath)
 }
 
 // getTempFolder gets the temp folder for generate logs
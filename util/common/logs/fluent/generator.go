@@ -0,0 +1,242 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fluent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/exp/slices"
+)
+
+// Forward protocol specification
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1
+const (
+	DefaultAddress = "127.0.0.1:24224"
+	DefaultTag     = "cwagent.test.forward"
+	LogLine        = "# %d - This is a fluent forward log line."
+
+	// MessageMode sends one event per message: [tag, time, record, option]
+	MessageMode = "message"
+	// ForwardMode sends a batch of events per message: [tag, [[time, record], ...], option]
+	ForwardMode = "forward"
+	// PackedForwardMode sends a batch of msgpack encoded events as a binary stream: [tag, bin, option]
+	PackedForwardMode = "packed_forward"
+
+	eventTimeExtID   = 0
+	defaultBatchSize = 100
+	ackTimeout       = 30 * time.Second
+)
+
+var SupportedModes = []string{MessageMode, ForwardMode, PackedForwardMode}
+
+func init() {
+	msgpack.RegisterExt(eventTimeExtID, (*EventTime)(nil))
+}
+
+// EventTime is the nanosecond precision timestamp defined by the forward protocol as msgpack ext type 0
+type EventTime time.Time
+
+func (t *EventTime) MarshalMsgpack() ([]byte, error) {
+	b := make([]byte, 8)
+	tm := time.Time(*t)
+	binary.BigEndian.PutUint32(b, uint32(tm.Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(tm.Nanosecond()))
+	return b, nil
+}
+
+func (t *EventTime) UnmarshalMsgpack(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("invalid EventTime length %d", len(b))
+	}
+	*t = EventTime(time.Unix(int64(binary.BigEndian.Uint32(b)), int64(binary.BigEndian.Uint32(b[4:]))))
+	return nil
+}
+
+type GeneratorConfig struct {
+	// Address is the forward input (e.g Fluent Bit or Fluentd) to send the events to
+	Address string
+	// Tag is the fluent tag for every event
+	Tag string
+	// Mode is the forward protocol carrier mode (message, forward or packed_forward)
+	Mode string
+	// RequireAck asks the server to acknowledge every message and fails if the acknowledgement does not match
+	RequireAck bool
+	// BatchSize is the number of events in each forward and packed_forward message
+	BatchSize int
+	// Interval is how often a new batch of events is sent
+	Interval time.Duration
+	// EventsPerInterval is the number of events sent every interval
+	EventsPerInterval int
+}
+
+type Generator struct {
+	cfg  GeneratorConfig
+	conn net.Conn
+}
+
+type entry struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	Time     *EventTime
+	Record   map[string]interface{}
+}
+
+func NewGenerator(cfg GeneratorConfig) (*Generator, error) {
+	if cfg.Address == "" {
+		cfg.Address = DefaultAddress
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = DefaultTag
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = ForwardMode
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if !slices.Contains(SupportedModes, cfg.Mode) {
+		return nil, fmt.Errorf("fluent forward mode %s is not supported, only support %v", cfg.Mode, SupportedModes)
+	}
+	return &Generator{cfg: cfg}, nil
+}
+
+// SendEvents connects to the forward input and sends the configured number of events every interval
+// until the duration has elapsed or the context is done
+func (g *Generator) SendEvents(ctx context.Context, duration time.Duration) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", g.cfg.Address)
+	if err != nil {
+		return err
+	}
+	g.conn = conn
+	defer conn.Close()
+
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+	endTimeout := time.After(duration)
+
+	// Sending the events within the first minute before the ticker kicks in the next minute
+	if err := g.sendInterval(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := g.sendInterval(); err != nil {
+				return err
+			}
+		case <-endTimeout:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (g *Generator) sendInterval() error {
+	events := make([]entry, 0, g.cfg.EventsPerInterval)
+	for i := 0; i < g.cfg.EventsPerInterval; i++ {
+		now := EventTime(time.Now())
+		events = append(events, entry{
+			Time: &now,
+			Record: map[string]interface{}{
+				"message": fmt.Sprintf(LogLine, i),
+				"mode":    g.cfg.Mode,
+			},
+		})
+	}
+
+	if g.cfg.Mode == MessageMode {
+		for _, event := range events {
+			if err := g.send(1, g.cfg.Tag, event.Time, event.Record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for start := 0; start < len(events); start += g.cfg.BatchSize {
+		end := start + g.cfg.BatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := g.sendBatch(events[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) sendBatch(events []entry) error {
+	if g.cfg.Mode == ForwardMode {
+		return g.send(len(events), g.cfg.Tag, events)
+	}
+
+	var stream bytes.Buffer
+	encoder := msgpack.NewEncoder(&stream)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return g.send(len(events), g.cfg.Tag, stream.Bytes())
+}
+
+// send encodes the message with the option map as the last element and waits for the acknowledgement if required
+func (g *Generator) send(size int, message ...interface{}) error {
+	option := map[string]interface{}{"size": size}
+	var chunk string
+	if g.cfg.RequireAck {
+		var err error
+		if chunk, err = newChunkID(); err != nil {
+			return err
+		}
+		option["chunk"] = chunk
+	}
+
+	payload, err := msgpack.Marshal(append(message, option))
+	if err != nil {
+		return err
+	}
+	if _, err = g.conn.Write(payload); err != nil {
+		return err
+	}
+
+	if !g.cfg.RequireAck {
+		return nil
+	}
+	return g.readAck(chunk)
+}
+
+func (g *Generator) readAck(chunk string) error {
+	if err := g.conn.SetReadDeadline(time.Now().Add(ackTimeout)); err != nil {
+		return err
+	}
+	defer g.conn.SetReadDeadline(time.Time{})
+
+	var response map[string]interface{}
+	if err := msgpack.NewDecoder(g.conn).Decode(&response); err != nil {
+		return fmt.Errorf("failed to read ack for chunk %s: %w", chunk, err)
+	}
+	if ack, ok := response["ack"].(string); !ok || ack != chunk {
+		return fmt.Errorf("ack %v does not match chunk %s", response["ack"], chunk)
+	}
+	return nil
+}
+
+func newChunkID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package integrity
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// LogLine keeps the "# %d - This is a log line." prefix the log validations look for and appends the fields
+// needed to check the delivery of every line
+const LogLine = "# %d - This is a log line. run_id=%s file_id=%s seq=%d emit_ts=%d\n"
+
+var logLinePattern = regexp.MustCompile(`run_id=(\S+) file_id=(\S+) seq=(\d+) emit_ts=(\d+)`)
+
+// Record is the delivery information parsed from a generated log line
+type Record struct {
+	RunID    string
+	FileID   string
+	Sequence int64
+	EmitTime time.Time
+}
+
+// ParseLine returns the delivery information of a generated log line or false if the line was not generated
+// by a Tracker
+func ParseLine(message string) (Record, bool) {
+	match := logLinePattern.FindStringSubmatch(message)
+	if match == nil {
+		return Record{}, false
+	}
+	sequence, err := strconv.ParseInt(match[3], 10, 64)
+	if err != nil {
+		return Record{}, false
+	}
+	emitTime, err := strconv.ParseInt(match[4], 10, 64)
+	if err != nil {
+		return Record{}, false
+	}
+	return Record{
+		RunID:    match[1],
+		FileID:   match[2],
+		Sequence: sequence,
+		EmitTime: time.UnixMilli(emitTime),
+	}, true
+}
+
+// Tracker numbers the log lines written to every file during a run so the delivered log events can be
+// checked for lost, duplicated and out of order lines
+type Tracker struct {
+	runID string
+	mu    sync.Mutex
+	files map[string]int64
+}
+
+func NewTracker(runID string) *Tracker {
+	return &Tracker{runID: runID, files: map[string]int64{}}
+}
+
+// RunID is added to every log line so the checker ignores the log events from other runs
+func (t *Tracker) RunID() string {
+	return t.runID
+}
+
+// NextLine returns the next log line for the file with its sequence number and the current time as emit time
+func (t *Tracker) NextLine(fileID string, index int) string {
+	t.mu.Lock()
+	sequence := t.files[fileID]
+	t.files[fileID]++
+	t.mu.Unlock()
+	return fmt.Sprintf(LogLine, index, t.runID, fileID, sequence, time.Now().UnixMilli())
+}
+
+// Written returns the number of log lines written to the file
+func (t *Tracker) Written(fileID string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.files[fileID]
+}
+
+// FileIDs returns every file the tracker has written log lines to
+func (t *Tracker) FileIDs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fileIDs := make([]string, 0, len(t.files))
+	for fileID := range t.files {
+		fileIDs = append(fileIDs, fileID)
+	}
+	sort.Strings(fileIDs)
+	return fileIDs
+}
+
+// Report is the delivery result of the log lines written to a single file
+type Report struct {
+	FileID     string
+	Written    int64
+	Received   int
+	Lost       int64
+	Duplicated int
+	OutOfOrder int
+	// Latencies are the delays from writing each unique log line to its ingestion by CloudWatch Logs
+	Latencies []time.Duration
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("file %s: written %d, received %d, lost %d, duplicated %d, out of order %d",
+		r.FileID, r.Written, r.Received, r.Lost, r.Duplicated, r.OutOfOrder)
+}
+
+// Check compares the log events delivered for a file with the log lines the tracker has written to it.
+// Log events from other runs or files are ignored.
+func (t *Tracker) Check(fileID string, events []types.OutputLogEvent) Report {
+	report := Report{FileID: fileID, Written: t.Written(fileID)}
+
+	seen := map[int64]bool{}
+	lastSequence := int64(-1)
+	for _, event := range events {
+		if event.Message == nil {
+			continue
+		}
+		record, ok := ParseLine(*event.Message)
+		if !ok || record.RunID != t.runID || record.FileID != fileID {
+			continue
+		}
+		report.Received++
+
+		if seen[record.Sequence] {
+			report.Duplicated++
+			continue
+		}
+		seen[record.Sequence] = true
+
+		if record.Sequence < lastSequence {
+			report.OutOfOrder++
+		} else {
+			lastSequence = record.Sequence
+		}
+
+		if event.IngestionTime != nil {
+			report.Latencies = append(report.Latencies, time.UnixMilli(*event.IngestionTime).Sub(record.EmitTime))
+		}
+	}
+
+	for sequence := int64(0); sequence < report.Written; sequence++ {
+		if !seen[sequence] {
+			report.Lost++
+		}
+	}
+	return report
+}
@@ -24,36 +24,50 @@ import (
 	"collectd.org/network"
 	"github.com/DataDog/datadog-go/statsd"
 	"github.com/prozz/aws-embedded-metrics-golang/emf"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/metrics/otlp"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/metrics/probe"
 )
 
 const SleepDuration = 5 * time.Second
 
+// metricIngestionProbe records the sentinel metrics sent by the validator
+var metricIngestionProbe = probe.NewRecorder()
+
 const TracesEndpoint = "4316/v1/traces"
 const MetricEndpoint = "4316/v1/metrics"
 
-// StartSendingMetrics will generate metrics load based on the receiver (e.g 5000 statsd metrics per minute)
-func StartSendingMetrics(receiver string, duration, sendingInterval time.Duration, metricPerInterval int, metricLogGroup, metricNamespace string) (err error) {
-	go func() {
-		switch receiver {
-		case "statsd":
-			err = SendStatsdMetrics(metricPerInterval, []string{}, sendingInterval, duration)
-		case "collectd":
-			err = SendCollectDMetrics(metricPerInterval, sendingInterval, duration)
-		case "emf":
-			err = SendEMFMetrics(metricPerInterval, metricLogGroup, metricNamespace, sendingInterval, duration)
-		case "app_signals":
-			err = SendAppSignalMetrics(duration) //does app signals have dimension for metric?
-		case "traces":
-			err = SendAppSignalsTraceMetrics(duration) //does app signals have dimension for metric?
-
-		default:
-		}
-	}()
+// MetricIngestionProbe returns the recorder of the sentinel metrics sent by the validator when ingestion_probe
+// is enabled in the validator config
+func MetricIngestionProbe() *probe.Recorder {
+	return metricIngestionProbe
+}
 
-	return err
+// StartSendingMetrics will generate metrics load based on the receiver (e.g 5000 statsd metrics per minute)
+// until the duration has elapsed or the context is done, and returns the error of the load generator.
+// When the ingestion probe is not nil, statsd, collectd and emf also send a sentinel metric every interval to
+// measure the time from sending a metric to the metric being queryable in CloudWatch
+func StartSendingMetrics(ctx context.Context, receiver string, duration, sendingInterval time.Duration, metricPerInterval, metricCardinality int, instanceId, metricNamespace string, ingestionProbe *probe.Recorder) error {
+	switch receiver {
+	case "statsd":
+		return SendStatsdMetrics(ctx, metricPerInterval, []string{}, sendingInterval, duration, ingestionProbe)
+	case "collectd":
+		return SendCollectDMetrics(ctx, metricPerInterval, sendingInterval, duration, ingestionProbe)
+	case "emf":
+		// the emf logs go to a log group named after the instance
+		return SendEMFMetrics(ctx, metricPerInterval, instanceId, metricNamespace, sendingInterval, duration, ingestionProbe)
+	case "otlp":
+		return SendOtlpMetrics(ctx, metricPerInterval, metricCardinality, instanceId, sendingInterval, duration)
+	case "app_signals":
+		return SendAppSignalMetrics(ctx, duration) //does app signals have dimension for metric?
+	case "traces":
+		return SendAppSignalsTraceMetrics(ctx, duration) //does app signals have dimension for metric?
+	default:
+		return fmt.Errorf("receiver %s has no metrics load generator", receiver)
+	}
 }
 
-func SendAppSignalsTraceMetrics(duration time.Duration) error {
+func SendAppSignalsTraceMetrics(ctx context.Context, duration time.Duration) error {
 	baseDir := getBaseDir()
 
 	for i := 0; i < int(duration/(5*time.Second)); i++ {
@@ -67,7 +81,9 @@ func SendAppSignalsTraceMetrics(duration time.Duration) error {
 			return err
 		}
 
-		time.Sleep(5 * time.Second)
+		if err = Sleep(ctx, 5*time.Second); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -106,9 +122,9 @@ func processTraceFile(filePath string, startTime int64, traceID string) error {
 	return nil
 }
 
-func SendCollectDMetrics(metricPerInterval int, sendingInterval, duration time.Duration) error {
+func SendCollectDMetrics(ctx context.Context, metricPerInterval int, sendingInterval, duration time.Duration, ingestionProbe *probe.Recorder) error {
 	// https://github.com/collectd/go-collectd/tree/92e86f95efac5eb62fa84acc6033e7a57218b606
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	client, err := network.Dial(
@@ -156,12 +172,18 @@ func SendCollectDMetrics(metricPerInterval int, sendingInterval, duration time.D
 		}
 	}
 
-	time.Sleep(30 * time.Second)
+	if err := Sleep(ctx, 30*time.Second); err != nil {
+		return err
+	}
 
 	if err := client.Flush(); err != nil {
 		return err
 	}
 
+	if err := sendCollectDProbe(ctx, client, ingestionProbe); err != nil {
+		return err
+	}
+
 	for {
 		select {
 		case <-ticker.C:
@@ -196,12 +218,45 @@ func SendCollectDMetrics(metricPerInterval int, sendingInterval, duration time.D
 			if err := client.Flush(); err != nil {
 				return err
 			}
+
+			if err := sendCollectDProbe(ctx, client, ingestionProbe); err != nil {
+				return err
+			}
 		case <-endTimeout:
 			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
 }
+
+// sendCollectDProbe sends the collectd_probe_value sentinel gauge and flushes it right away
+func sendCollectDProbe(ctx context.Context, client *network.Client, ingestionProbe *probe.Recorder) error {
+	if ingestionProbe == nil {
+		return nil
+	}
+	value := ingestionProbe.Next("collectd")
+	emitTime := time.Now()
+	err := client.Write(ctx, &api.ValueList{
+		Identifier: api.Identifier{
+			Host:   exec.Hostname(),
+			Plugin: probe.MetricName,
+			Type:   "gauge",
+		},
+		Time:     emitTime,
+		Interval: time.Minute,
+		Values:   []api.Value{api.Gauge(value)},
+	})
+	if err != nil && !errors.Is(err, network.ErrNotEnoughSpace) {
+		return err
+	}
+	if err = client.Flush(); err != nil {
+		return err
+	}
+	ingestionProbe.Record("collectd", fmt.Sprint("collectd_", probe.MetricName, "_value"), map[string]string{"type": "gauge"}, value, emitTime)
+	return nil
+}
 func processFile(filePath string, startTime int64) error {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -226,7 +281,7 @@ func processFile(filePath string, startTime int64) error {
 
 }
 
-func SendAppSignalMetrics(duration time.Duration) error {
+func SendAppSignalMetrics(ctx context.Context, duration time.Duration) error {
 	// The bash script to be executed asynchronously.
 	dir, err := os.Getwd()
 	if err != nil {
@@ -263,14 +318,16 @@ func SendAppSignalMetrics(duration time.Duration) error {
 			return err
 		}
 
-		time.Sleep(5 * time.Second)
+		if err = Sleep(ctx, 5*time.Second); err != nil {
+			return err
+		}
 	}
 
 	return nil
 
 }
 
-func SendStatsdMetrics(metricPerInterval int, metricDimension []string, sendingInterval, duration time.Duration) error {
+func SendStatsdMetrics(ctx context.Context, metricPerInterval int, metricDimension []string, sendingInterval, duration time.Duration, ingestionProbe *probe.Recorder) error {
 	// https://github.com/DataDog/datadog-go#metrics
 	client, err := statsd.New("127.0.0.1:8125", statsd.WithMaxMessagesPerPayload(100), statsd.WithNamespace("statsd"), statsd.WithoutTelemetry())
 
@@ -294,6 +351,10 @@ func SendStatsdMetrics(metricPerInterval int, metricDimension []string, sendingI
 		}
 	}
 
+	if err := sendStatsdProbe(client, metricDimension, ingestionProbe); err != nil {
+		return err
+	}
+
 	for {
 		select {
 		case <-ticker.C:
@@ -301,15 +362,59 @@ func SendStatsdMetrics(metricPerInterval int, metricDimension []string, sendingI
 				client.Count(fmt.Sprint("counter_", t), int64(t), metricDimension, 1.0)
 				client.Gauge(fmt.Sprint("gauge_", t), float64(t), metricDimension, 1.0)
 			}
+
+			if err := sendStatsdProbe(client, metricDimension, ingestionProbe); err != nil {
+				return err
+			}
 		case <-endTimeout:
 			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 }
 
-func SendEMFMetrics(metricPerInterval int, metricLogGroup, metricNamespace string, sendingInterval, duration time.Duration) error {
+// sendStatsdProbe sends the statsd_probe sentinel gauge and flushes it right away
+func sendStatsdProbe(client *statsd.Client, metricDimension []string, ingestionProbe *probe.Recorder) error {
+	if ingestionProbe == nil {
+		return nil
+	}
+	value := ingestionProbe.Next("statsd")
+	emitTime := time.Now()
+	if err := client.Gauge(probe.MetricName, value, metricDimension, 1.0); err != nil {
+		return err
+	}
+	if err := client.Flush(); err != nil {
+		return err
+	}
+
+	// The agent adds the metric_type dimension to every statsd metric
+	dimensions := map[string]string{"metric_type": "gauge"}
+	for _, tag := range metricDimension {
+		if name, value, ok := strings.Cut(tag, ":"); ok {
+			dimensions[name] = value
+		}
+	}
+	ingestionProbe.Record("statsd", fmt.Sprint("statsd_", probe.MetricName), dimensions, value, emitTime)
+	return nil
+}
+
+// SendOtlpMetrics sends gauges, sums, histograms and exponential histograms over both OTLP gRPC and HTTP
+// with the given number of unique attribute sets per metric
+func SendOtlpMetrics(ctx context.Context, metricPerInterval, metricCardinality int, instanceId string, sendingInterval, duration time.Duration) error {
+	return otlp.SendMetrics(ctx, otlp.MetricGeneratorConfig{
+		Interval:           sendingInterval,
+		MetricsPerInterval: metricPerInterval,
+		Cardinality:        metricCardinality,
+		Protocols:          otlp.SupportedProtocols,
+		InstanceId:         instanceId,
+	}, duration)
+}
+
+func SendEMFMetrics(ctx context.Context, metricPerInterval int, metricLogGroup, metricNamespace string, sendingInterval, duration time.Duration, ingestionProbe *probe.Recorder) error {
 	// github.com/prozz/aws-embedded-metrics-golang/emf
-	conn, err := net.DialTimeout("tcp", "127.0.0.1:25888", time.Millisecond*10000)
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", "127.0.0.1:25888")
 	if err != nil {
 		return err
 	}
@@ -331,6 +436,8 @@ func SendEMFMetrics(metricPerInterval int, metricLogGroup, metricNamespace strin
 
 	}
 
+	sendEMFProbe(conn, metricLogGroup, metricNamespace, ingestionProbe)
+
 	for {
 		select {
 		case <-ticker.C:
@@ -344,9 +451,30 @@ func SendEMFMetrics(metricPerInterval int, metricLogGroup, metricNamespace strin
 					Log()
 
 			}
+
+			sendEMFProbe(conn, metricLogGroup, metricNamespace, ingestionProbe)
 		case <-endTimeout:
 			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
 }
+
+// sendEMFProbe sends the emf_probe sentinel metric with the same InstanceId dimension as the other emf metrics
+func sendEMFProbe(conn net.Conn, metricLogGroup, metricNamespace string, ingestionProbe *probe.Recorder) {
+	if ingestionProbe == nil {
+		return
+	}
+	value := ingestionProbe.Next("emf")
+	emitTime := time.Now()
+	emf.New(emf.WithWriter(conn), emf.WithLogGroup(metricLogGroup)).
+		Namespace(metricNamespace).
+		DimensionSet(
+			emf.NewDimension("InstanceId", metricLogGroup),
+		).
+		MetricFloat(fmt.Sprint("emf_", probe.MetricName), value).
+		Log()
+	ingestionProbe.Record("emf", fmt.Sprint("emf_", probe.MetricName), nil, value, emitTime)
+}
@@ -0,0 +1,254 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.uber.org/multierr"
+)
+
+const (
+	serviceName = "load-generator"
+
+	GrpcEndpoint = "127.0.0.1:4317"
+	HttpEndpoint = "127.0.0.1:4318"
+
+	ProtocolGrpc = "grpc"
+	ProtocolHttp = "http"
+
+	// The OTLP generator splits the metrics per interval evenly across these metric types
+	// (e.g 100 metrics per interval will send 25 gauges, 25 sums, 25 histograms and 25 exponential histograms)
+	gaugePrefix                = "otlp_gauge_"
+	sumPrefix                  = "otlp_sum_"
+	histogramPrefix            = "otlp_histogram_"
+	exponentialHistogramPrefix = "otlp_exponential_histogram_"
+	numberOfMetricTypes        = 4
+)
+
+var SupportedProtocols = []string{ProtocolGrpc, ProtocolHttp}
+
+type MetricGeneratorConfig struct {
+	// Interval is how often the generator records and exports a new batch of datapoints
+	Interval time.Duration
+	// MetricsPerInterval is the number of unique metric names sent for each protocol every interval
+	MetricsPerInterval int
+	// Cardinality is the number of unique attribute sets sent for every metric name
+	Cardinality int
+	// Protocols are the OTLP transports the metrics are sent over (e.g grpc, http)
+	Protocols []string
+	// InstanceId is added as an attribute to every datapoint so the metrics can be found by the validator
+	InstanceId string
+}
+
+type protocolGenerator struct {
+	protocol string
+	exporter sdkmetric.Exporter
+	reader   *sdkmetric.ManualReader
+	provider *sdkmetric.MeterProvider
+
+	attributeSets []attribute.Set
+	sums          []metric.Int64Counter
+	histograms    []metric.Float64Histogram
+	expHistograms []metric.Float64Histogram
+}
+
+// SendMetrics will generate OTLP gauges, sums, histograms and exponential histograms every interval for each
+// configured protocol until the duration has elapsed or the context is done
+func SendMetrics(ctx context.Context, cfg MetricGeneratorConfig, duration time.Duration) error {
+	if cfg.Cardinality < 1 {
+		cfg.Cardinality = 1
+	}
+	if len(cfg.Protocols) == 0 {
+		cfg.Protocols = SupportedProtocols
+	}
+
+	var generators []*protocolGenerator
+	for _, protocol := range cfg.Protocols {
+		generator, err := newProtocolGenerator(ctx, protocol, cfg)
+		if err != nil {
+			return err
+		}
+		generators = append(generators, generator)
+	}
+
+	defer func() {
+		for _, generator := range generators {
+			// The exporters still flush when the context is done
+			generator.shutdown(context.Background())
+		}
+	}()
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	endTimeout := time.After(duration)
+
+	// Sending the otlp metric within the first minute before the ticker kicks in the next minute
+	if err := sendAll(ctx, generators); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := sendAll(ctx, generators); err != nil {
+				return err
+			}
+		case <-endTimeout:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func sendAll(ctx context.Context, generators []*protocolGenerator) error {
+	var multiErr error
+	for _, generator := range generators {
+		if err := generator.send(ctx); err != nil {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("failed to send otlp metrics over %s: %w", generator.protocol, err))
+		}
+	}
+	return multiErr
+}
+
+func newProtocolGenerator(ctx context.Context, protocol string, cfg MetricGeneratorConfig) (*protocolGenerator, error) {
+	exporter, err := newExporter(ctx, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	// The generator controls when the metrics are collected and exported so every interval produces exactly
+	// one datapoint per series, the same way the statsd and collectd generators send one batch per interval
+	reader := sdkmetric.NewManualReader(
+		sdkmetric.WithTemporalitySelector(exporter.Temporality),
+		sdkmetric.WithAggregationSelector(exporter.Aggregation),
+	)
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		)),
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: exponentialHistogramPrefix + "*"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}},
+		)),
+	)
+
+	g := &protocolGenerator{
+		protocol: protocol,
+		exporter: exporter,
+		reader:   reader,
+		provider: provider,
+	}
+
+	for i := 0; i < cfg.Cardinality; i++ {
+		g.attributeSets = append(g.attributeSets, attribute.NewSet(
+			attribute.String("InstanceId", cfg.InstanceId),
+			attribute.String("protocol", protocol),
+			attribute.String("series", fmt.Sprint(i)),
+		))
+	}
+
+	// Always send at least one metric of each type even when the rate is lower than the number of metric types
+	metricsPerType := cfg.MetricsPerInterval / numberOfMetricTypes
+	if metricsPerType < 1 {
+		metricsPerType = 1
+	}
+	if err = g.registerInstruments(metricsPerType); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func newExporter(ctx context.Context, protocol string) (sdkmetric.Exporter, error) {
+	switch protocol {
+	case ProtocolGrpc:
+		return otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithInsecure(), otlpmetricgrpc.WithEndpoint(GrpcEndpoint))
+	case ProtocolHttp:
+		return otlpmetrichttp.New(ctx, otlpmetrichttp.WithInsecure(), otlpmetrichttp.WithEndpoint(HttpEndpoint))
+	default:
+		return nil, fmt.Errorf("otlp protocol %s is not supported, only support %v", protocol, SupportedProtocols)
+	}
+}
+
+func (g *protocolGenerator) registerInstruments(metricsPerType int) error {
+	meter := g.provider.Meter(serviceName)
+
+	for t := 1; t <= metricsPerType; t++ {
+		value := float64(t)
+		gauge, err := meter.Float64ObservableGauge(fmt.Sprint(gaugePrefix, t))
+		if err != nil {
+			return err
+		}
+		_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			for _, set := range g.attributeSets {
+				o.ObserveFloat64(gauge, value, metric.WithAttributeSet(set))
+			}
+			return nil
+		}, gauge)
+		if err != nil {
+			return err
+		}
+
+		sum, err := meter.Int64Counter(fmt.Sprint(sumPrefix, t))
+		if err != nil {
+			return err
+		}
+		g.sums = append(g.sums, sum)
+
+		histogram, err := meter.Float64Histogram(fmt.Sprint(histogramPrefix, t))
+		if err != nil {
+			return err
+		}
+		g.histograms = append(g.histograms, histogram)
+
+		expHistogram, err := meter.Float64Histogram(fmt.Sprint(exponentialHistogramPrefix, t))
+		if err != nil {
+			return err
+		}
+		g.expHistograms = append(g.expHistograms, expHistogram)
+	}
+
+	return nil
+}
+
+// send records one value per series for every synchronous instrument, then collects and exports them
+func (g *protocolGenerator) send(ctx context.Context) error {
+	for _, set := range g.attributeSets {
+		opt := metric.WithAttributeSet(set)
+		for i := range g.sums {
+			value := i + 1
+			g.sums[i].Add(ctx, int64(value), opt)
+			g.histograms[i].Record(ctx, float64(value), opt)
+			g.expHistograms[i].Record(ctx, float64(value), opt)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := g.reader.Collect(ctx, &rm); err != nil {
+		return err
+	}
+	return g.exporter.Export(ctx, &rm)
+}
+
+func (g *protocolGenerator) shutdown(ctx context.Context) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_ = g.provider.Shutdown(timeoutCtx)
+	_ = g.exporter.Shutdown(timeoutCtx)
+}
@@ -0,0 +1,250 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+)
+
+const (
+	// MetricName is the name of the sentinel metric sent by every receiver (e.g statsd sends probe as statsd_probe)
+	MetricName = "probe"
+
+	// GetMetricData supports up to 500 queries per request
+	maxQueriesPerRequest = 500
+	queryPeriod          = 60
+)
+
+// Sentinel is a metric sent with a known value and emit time. QueryableTime is the first time the poller
+// finds the sentinel value in CloudWatch and is zero until then.
+type Sentinel struct {
+	Receiver      string
+	MetricName    string
+	Dimensions    []types.Dimension
+	Value         float64
+	EmitTime      time.Time
+	QueryableTime time.Time
+}
+
+// Latency is the time from emitting the sentinel to the sentinel being queryable in CloudWatch
+func (s *Sentinel) Latency() time.Duration {
+	if s.QueryableTime.IsZero() {
+		return 0
+	}
+	return s.QueryableTime.Sub(s.EmitTime)
+}
+
+// Recorder keeps the sentinels emitted by the metric generators so their ingestion latency can be measured
+type Recorder struct {
+	mu        sync.Mutex
+	sentinels []*Sentinel
+	values    map[string]float64
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{values: map[string]float64{}}
+}
+
+// Next returns the next sentinel value for the receiver. The values increase so each sentinel is distinguishable
+// from the previous ones in the same metric.
+func (r *Recorder) Next(receiver string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[receiver]++
+	return r.values[receiver]
+}
+
+// Record keeps a sentinel after it has been sent to the agent
+func (r *Recorder) Record(receiver, metricName string, dimensions map[string]string, value float64, emitTime time.Time) {
+	sentinel := &Sentinel{
+		Receiver:   receiver,
+		MetricName: metricName,
+		Value:      value,
+		EmitTime:   emitTime,
+	}
+	for name, value := range dimensions {
+		sentinel.Dimensions = append(sentinel.Dimensions, types.Dimension{Name: aws.String(name), Value: aws.String(value)})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sentinels = append(r.sentinels, sentinel)
+}
+
+// Reset forgets the recorded sentinels. The sentinel values keep increasing so the sentinels recorded afterwards
+// are distinguishable from the forgotten ones.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sentinels = nil
+}
+
+// Sentinels returns a copy of every recorded sentinel
+func (r *Recorder) Sentinels() []Sentinel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sentinels := make([]Sentinel, 0, len(r.sentinels))
+	for _, sentinel := range r.sentinels {
+		sentinels = append(sentinels, *sentinel)
+	}
+	return sentinels
+}
+
+// Poll queries CloudWatch for the pending sentinels every poll interval and records when each one first becomes
+// queryable, until the context is done. The instance id dimension is added to every sentinel since the agent
+// appends it to the metrics.
+func (r *Recorder) Poll(ctx context.Context, namespace, instanceId string, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.pollOnce(namespace, instanceId); err != nil {
+				log.Printf("Failed to poll the metric ingestion probes: %v", err)
+			}
+		}
+	}
+}
+
+func (r *Recorder) pollOnce(namespace, instanceId string) error {
+	r.mu.Lock()
+	var pending []*Sentinel
+	for _, sentinel := range r.sentinels {
+		if sentinel.QueryableTime.IsZero() {
+			pending = append(pending, sentinel)
+		}
+	}
+	r.mu.Unlock()
+
+	for start := 0; start < len(pending); start += maxQueriesPerRequest {
+		end := start + maxQueriesPerRequest
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := r.query(namespace, instanceId, pending[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) query(namespace, instanceId string, sentinels []*Sentinel) error {
+	var (
+		queries   []types.MetricDataQuery
+		startTime = time.Now()
+		endTime   = time.Now().Add(time.Minute)
+	)
+	for i, sentinel := range sentinels {
+		if emitMinute := sentinel.EmitTime.Truncate(time.Minute); emitMinute.Before(startTime) {
+			startTime = emitMinute
+		}
+		queries = append(queries, types.MetricDataQuery{
+			Id: aws.String(fmt.Sprint("probe", i)),
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(sentinel.MetricName),
+					Dimensions: append([]types.Dimension{{Name: aws.String("InstanceId"), Value: aws.String(instanceId)}}, sentinel.Dimensions...),
+				},
+				Period: aws.Int32(queryPeriod),
+				Stat:   aws.String(string(types.StatisticMaximum)),
+			},
+		})
+	}
+
+	output, err := awsservice.GetMetricData(queries, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	queriedTime := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, result := range output.MetricDataResults {
+		var i int
+		if _, err = fmt.Sscanf(*result.Id, "probe%d", &i); err != nil || i >= len(sentinels) {
+			continue
+		}
+		sentinel := sentinels[i]
+		emitPeriod := sentinel.EmitTime.Truncate(queryPeriod * time.Second)
+		for j, timestamp := range result.Timestamps {
+			if timestamp.Equal(emitPeriod) && result.Values[j] >= sentinel.Value {
+				sentinel.QueryableTime = queriedTime
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Percentiles summarizes the ingestion latency of the sentinels sent by a receiver
+type Percentiles struct {
+	// Count is the number of sentinels that became queryable
+	Count   int
+	Missing int
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+func (p Percentiles) String() string {
+	return fmt.Sprintf("count %d, missing %d, p50 %v, p90 %v, p99 %v, max %v", p.Count, p.Missing, p.P50, p.P90, p.P99, p.Max)
+}
+
+// Summarize returns the ingestion latency percentiles for every receiver. Sentinels that never became
+// queryable are counted as missing.
+func Summarize(sentinels []Sentinel) map[string]Percentiles {
+	latencies := map[string][]time.Duration{}
+	missing := map[string]int{}
+	for _, sentinel := range sentinels {
+		if sentinel.QueryableTime.IsZero() {
+			missing[sentinel.Receiver]++
+			continue
+		}
+		latencies[sentinel.Receiver] = append(latencies[sentinel.Receiver], sentinel.Latency())
+	}
+
+	summary := map[string]Percentiles{}
+	for receiver := range missing {
+		summary[receiver] = Percentiles{Missing: missing[receiver]}
+	}
+	for receiver, values := range latencies {
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		percentiles := Percentiles{Count: len(values), Missing: missing[receiver]}
+		if len(values) > 0 {
+			percentiles.P50 = percentile(values, 0.50)
+			percentiles.P90 = percentile(values, 0.90)
+			percentiles.P99 = percentile(values, 0.99)
+			percentiles.Max = values[len(values)-1]
+		}
+		summary[receiver] = percentiles
+	}
+	return summary
+}
+
+// percentile uses the nearest rank on sorted values
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(math.Ceil(float64(len(sorted))*p)) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
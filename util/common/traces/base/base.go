@@ -117,13 +117,34 @@ func SegmentValidationTest(t *testing.T, traceTest TraceTestConfig, segments []t
 	return nil
 
 }
-func GenerateTraces(traceTest TraceTestConfig) error {
+
+// GenerateTraces sends traces for the agent runtime and returns the error of the generator. The generator stops
+// early when it fails or the context is done. A generator done early without error still waits for the agent
+// runtime and the agent shutdown delay.
+func GenerateTraces(ctx context.Context, traceTest TraceTestConfig) error {
 	common.CopyFile(traceTest.AgentConfigPath, common.ConfigOutputPath)
+	generatorErr := make(chan error, 1)
 	go func() {
-		traceTest.Generator.StartSendingTraces(context.Background())
+		generatorErr <- traceTest.Generator.StartSendingTraces(ctx)
 	}()
-	time.Sleep(traceTest.AgentRuntime)
-	traceTest.Generator.StopSendingTraces()
-	time.Sleep(AGENT_SHUTDOWN_DELAY)
-	return nil
+
+	timer := time.NewTimer(traceTest.AgentRuntime)
+	defer timer.Stop()
+	select {
+	case err := <-generatorErr:
+		if err != nil {
+			return err
+		}
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case <-timer.C:
+		traceTest.Generator.StopSendingTraces()
+		if err := <-generatorErr; err != nil {
+			return err
+		}
+	}
+	return common.Sleep(ctx, AGENT_SHUTDOWN_DELAY)
 }
@@ -4,6 +4,7 @@
 package traces
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -11,7 +12,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/util/common/traces/xray"
 )
 
-func StartTraceGeneration(receiver string, agentConfigPath string, agentRuntime time.Duration, traceSendingInterval time.Duration) error {
+func StartTraceGeneration(ctx context.Context, receiver string, agentConfigPath string, agentRuntime time.Duration, traceSendingInterval time.Duration) error {
 	cfg := base.TraceTestConfig{
 		Generator:       nil,
 		Name:            "",
@@ -42,6 +43,6 @@ func StartTraceGeneration(receiver string, agentConfigPath string, agentRuntime
 	default:
 		return fmt.Errorf("%s is not supported.", receiver)
 	}
-	err := base.GenerateTraces(cfg)
+	err := base.GenerateTraces(ctx, cfg)
 	return err
 }
@@ -45,6 +45,9 @@ func (g *OtlpTracesGenerator) StartSendingTraces(ctx context.Context) error {
 		case <-g.Done:
 			ticker.Stop()
 			return client.ForceFlush(ctx)
+		case <-ctx.Done():
+			ticker.Stop()
+			return ctx.Err()
 		case <-ticker.C:
 			if err = g.Generate(ctx); err != nil {
 				return err
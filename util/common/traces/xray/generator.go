@@ -32,6 +32,9 @@ func (g *XrayTracesGenerator) StartSendingTraces(ctx context.Context) error {
 		case <-g.Done:
 			ticker.Stop()
 			return nil
+		case <-ctx.Done():
+			ticker.Stop()
+			return ctx.Err()
 		case <-ticker.C:
 			if err := g.Generate(ctx); err != nil {
 				return err
@@ -4,27 +4,39 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"go.uber.org/multierr"
+	"golang.org/x/exp/slices"
+
 	"github.com/aws/amazon-cloudwatch-agent-test/test/acceptance"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/nvidia_gpu"
 	"github.com/aws/amazon-cloudwatch-agent-test/test/restart"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/diagnostics"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/models"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators"
 )
 
 var (
-	configPath      = flag.String("validator-config", "", "A yaml depicts test information")
-	preparationMode = flag.Bool("preparation-mode", false, "Prepare all the resources for the validation (e.g set up config) ")
-	testName        = flag.String("test-name", "", "Test name to execute")
-	assumeRoleArn   = flag.String("role-arn", "", "Arn for assume IAM role if any")
+	configPath        = flag.String("validator-config", "", "A yaml depicts test information")
+	preparationMode   = flag.Bool("preparation-mode", false, "Prepare all the resources for the validation (e.g set up config) ")
+	testName          = flag.String("test-name", "", "Test name to execute")
+	assumeRoleArn     = flag.String("role-arn", "", "Arn for assume IAM role if any")
+	dryRun            = flag.Bool("dry-run", false, "Cross-check the validator config with the agent config and print every problem without generating load")
+	agentConfigPath   = flag.String("agent-config", "", "Agent config for the dry run if cloudwatch_agent_config is not set (default agent_config.json next to the validator config)")
+	diagnosticsDir    = flag.String("diagnostics-dir", "", "Directory to write a diagnostics bundle to when the validation fails")
+	diagnosticsBucket = flag.String("diagnostics-bucket", "", "S3 bucket to upload the diagnostics bundle to (requires diagnostics-dir)")
+	timeout           = flag.Duration("timeout", 0, "Stop the validation and clean up after this long (e.g 45m). No timeout by default.")
 )
 
 func main() {
@@ -32,6 +44,15 @@ func main() {
 
 	startTime := time.Now()
 
+	// Ctrl-C and the CI stopping the validator stop the load and clean up instead of exiting right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
 	// validator calls test code to get around OOM issue on windows hosts while running go test
 	if len(*configPath) == 0 && len(*testName) > 0 {
 		// execute test without parsing or processing configuration yaml
@@ -51,6 +72,15 @@ func main() {
 		if err != nil {
 			log.Fatalf("Validator failed with %s: %v", *testName, err)
 		}
+	} else if *dryRun {
+		if err := models.LintValidateConfig(*configPath, *agentConfigPath); err != nil {
+			for _, e := range multierr.Errors(err) {
+				log.Printf("Problem: %v", e)
+			}
+			log.Fatalf("Dry run found %d problem(s) with %s", len(multierr.Errors(err)), *configPath)
+		}
+		log.Printf("Dry run found no problem with %s", *configPath)
+		os.Exit(0)
 	} else {
 		vConfig, err := models.NewValidateConfig(*configPath)
 		if err != nil {
@@ -64,8 +94,9 @@ func main() {
 
 			os.Exit(0)
 		}
-		err = validate(vConfig)
+		err = validate(ctx, vConfig)
 		if err != nil {
+			collectDiagnostics(vConfig)
 			log.Fatalf("Failed to validate: %v", err)
 		}
 	}
@@ -76,24 +107,48 @@ func main() {
 
 }
 
-func validate(vConfig models.ValidateConfig) error {
+func validate(ctx context.Context, vConfig models.ValidateConfig) error {
 	var err error
 	for i := 0; i < awsservice.StandardRetries; i++ {
-		err = validators.LaunchValidator(vConfig)
+		err = validators.LaunchValidator(ctx, vConfig)
 
 		if err == nil {
 			log.Printf("Test case: %s, validate type: %s has been successfully validated", vConfig.GetTestCase(), vConfig.GetValidateType())
 			return nil
 		}
-		time.Sleep(60 * time.Second)
 		log.Printf("test case: %s, validate type: %s, error: %v", vConfig.GetTestCase(), vConfig.GetValidateType(), err)
-		continue
+		// Retrying is pointless once the validator is interrupted or out of time
+		if sleepErr := common.Sleep(ctx, 60*time.Second); sleepErr != nil {
+			err = multierr.Append(err, sleepErr)
+			break
+		}
 	}
 
 	return fmt.Errorf("test case: %s, validate type: %s, error: %v", vConfig.GetTestCase(), vConfig.GetValidateType(), err)
 
 }
 
+// collectDiagnostics writes the agent diagnostics with the validator and agent configs when the diagnostics-dir is set
+func collectDiagnostics(vConfig models.ValidateConfig) {
+	if *diagnosticsDir == "" {
+		return
+	}
+	location, err := diagnostics.Collect(diagnostics.Options{
+		Name:      fmt.Sprintf("%s-%s", vConfig.GetTestCase(), vConfig.GetValidateType()),
+		Directory: *diagnosticsDir,
+		Bucket:    *diagnosticsBucket,
+		Files: map[string]string{
+			"validator.yaml":    *configPath,
+			"agent_config.json": vConfig.GetCloudWatchAgentConfigPath(),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to collect diagnostics: %v", err)
+		return
+	}
+	log.Printf("Diagnostics: %s", location)
+}
+
 func prepare(vConfig models.ValidateConfig) error {
 	var (
 		err                 error
@@ -103,6 +158,10 @@ func prepare(vConfig models.ValidateConfig) error {
 	)
 	switch dataType {
 	case "logs":
+		// The fluent receiver sends logs to the forward input directly instead of writing to monitored log files
+		if slices.Contains(vConfig.GetPluginsConfig(), "fluent") {
+			break
+		}
 		err = common.GenerateLogConfig(numberLogsMonitored, agentConfigFilePath)
 	default:
 	}
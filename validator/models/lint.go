@@ -0,0 +1,374 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/multierr"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultAgentConfigName is the CloudWatchAgent config stored next to every validator yaml in the test folders
+	defaultAgentConfigName = "agent_config.json"
+	// defaultMetricNamespace is the namespace the CloudWatchAgent uses when metrics.namespace is not set
+	defaultMetricNamespace = "CWAgent"
+)
+
+var supportedDataTypes = []string{"metrics", "logs", "traces"}
+
+// LintValidateConfig parses the validator yaml, loads the CloudWatchAgent config it references and cross-checks
+// them without generating any load. Every problem found is returned at once (use multierr.Errors to split them).
+// agentConfigPath overrides cloudwatch_agent_config; when both are empty or still a template placeholder
+// (e.g <cloudwatch_agent_config>), the agent_config.json next to the validator yaml is used.
+func LintValidateConfig(configPath, agentConfigPath string) error {
+	configPathBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("%v with file %s", err, configPath)
+	}
+
+	vConfig := validatorConfig{}
+	if err = yaml.Unmarshal(configPathBytes, &vConfig); err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(configPathBytes, &vConfig.document); err != nil {
+		return err
+	}
+
+	multiErr := lintValidatorConfig(vConfig)
+
+	if agentConfigPath == "" {
+		agentConfigPath = vConfig.ConfigPath
+	}
+	if agentConfigPath == "" || isPlaceholder(agentConfigPath) {
+		agentConfigPath = filepath.Join(filepath.Dir(configPath), defaultAgentConfigName)
+	}
+	agentConfig, err := readAgentConfig(agentConfigPath)
+	if err != nil {
+		return multierr.Append(multiErr, err)
+	}
+
+	return multierr.Combine(
+		multiErr,
+		lintReceivers(vConfig, agentConfig),
+		lintMetricValidation(vConfig, agentConfig),
+		lintLogValidation(vConfig, agentConfig),
+	)
+}
+
+// lintValidatorConfig checks the validator yaml on its own, the same way NewValidateConfig does but without
+// stopping at the first problem
+func lintValidatorConfig(vConfig validatorConfig) error {
+	var multiErr error
+
+	if validator, err := GetValidator(vConfig.ValidateType); err != nil {
+		multiErr = multierr.Append(multiErr, err)
+	} else if err = validateSchema(validator.Name, validator.ConfigSchema, vConfig.document); err != nil {
+		multiErr = multierr.Append(multiErr, err)
+	}
+
+	if !slices.Contains(supportedDataTypes, vConfig.DataType) {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("data type %q is not supported, only support %v", vConfig.DataType, supportedDataTypes))
+	}
+
+	if vConfig.AgentCollectionPeriod <= 0 {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("agent_collection_period needs to be a positive number of seconds, got %d", vConfig.AgentCollectionPeriod))
+	}
+
+	if len(vConfig.MetricValidation) > 0 && vConfig.MetricNamespace == "" {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("metric_namespace is required to validate %d metric(s)", len(vConfig.MetricValidation)))
+	}
+
+	for _, metric := range vConfig.MetricValidation {
+		if metric.MetricName == "" {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("metric_validation has a metric without metric_name"))
+		}
+		seen := map[string]bool{}
+		for _, dimension := range metric.MetricDimension {
+			if dimension.Name == "" || dimension.Value == "" {
+				multiErr = multierr.Append(multiErr, fmt.Errorf("metric %s has a dimension without name or value: %+v", metric.MetricName, dimension))
+			}
+			if seen[dimension.Name] {
+				multiErr = multierr.Append(multiErr, fmt.Errorf("metric %s has the dimension %s more than once", metric.MetricName, dimension.Name))
+			}
+			seen[dimension.Name] = true
+		}
+	}
+
+	for _, logValidation := range vConfig.LogValidation {
+		if logValidation.LogStream == "" {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("log_validation for %q has no log_stream", logValidation.LogValue))
+		}
+	}
+
+	return multiErr
+}
+
+// lintReceivers checks every receiver is registered, its config schema is satisfied and the CloudWatchAgent
+// config collects its data
+func lintReceivers(vConfig validatorConfig, agentConfig map[string]interface{}) error {
+	var multiErr error
+	for _, name := range vConfig.Receivers {
+		receiver, err := GetReceiver(name)
+		if err != nil {
+			multiErr = multierr.Append(multiErr, err)
+			continue
+		}
+		if err = validateSchema(receiver.Name, receiver.ConfigSchema, vConfig.document); err != nil {
+			multiErr = multierr.Append(multiErr, err)
+		}
+		if len(receiver.AgentConfigSections) == 0 {
+			continue
+		}
+		configured := false
+		for _, section := range receiver.AgentConfigSections {
+			if _, ok := lookup(agentConfig, strings.Split(section, ".")...); ok {
+				configured = true
+				break
+			}
+		}
+		if !configured {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("receiver %s is not configured in the agent config, expect one of %v", receiver.Name, receiver.AgentConfigSections))
+		}
+	}
+
+	if slices.Contains(vConfig.Receivers, "logs") && vConfig.DataType == "logs" && vConfig.NumberMonitoredLogs <= 0 {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("number_monitored_logs needs to be positive for the logs receiver, got %d", vConfig.NumberMonitoredLogs))
+	}
+	return multiErr
+}
+
+// lintMetricValidation checks the metric namespace and the metrics and dimensions in metric_validation can be
+// produced by the CloudWatchAgent config
+func lintMetricValidation(vConfig validatorConfig, agentConfig map[string]interface{}) error {
+	if len(vConfig.MetricValidation) == 0 {
+		return nil
+	}
+
+	var multiErr error
+	// The EMF generator sends its metrics to the namespace in the validator yaml
+	if namespaces := agentNamespaces(agentConfig); !slices.Contains(vConfig.Receivers, "emf") && !slices.Contains(namespaces, vConfig.MetricNamespace) {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("metric_namespace %q is not one of the namespaces in the agent config %v", vConfig.MetricNamespace, namespaces))
+	}
+
+	metricsCollected, _ := lookup(agentConfig, "metrics", "metrics_collected")
+	plugins, _ := metricsCollected.(map[string]interface{})
+	if len(plugins) > 0 {
+		if _, ok := lookup(agentConfig, "metrics", "append_dimensions", "InstanceId"); !ok {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("the validator queries the metrics with the InstanceId dimension but metrics.append_dimensions.InstanceId is not set in the agent config"))
+		}
+	}
+
+	for _, metric := range vConfig.MetricValidation {
+		plugin := metricPlugin(plugins, metric.MetricName)
+		if plugin == "" {
+			continue
+		}
+		if err := lintPluginMetric(plugin, plugins[plugin], metric); err != nil {
+			multiErr = multierr.Append(multiErr, err)
+		}
+	}
+	return multiErr
+}
+
+// lintPluginMetric checks the measurement and the dimensions of a metric collected by a metrics_collected plugin
+// (e.g procstat_cpu_usage with exe=cloudwatch-agent needs procstat to monitor the cloudwatch-agent exe with cpu_usage)
+func lintPluginMetric(plugin string, pluginConfig interface{}, metric MetricValidation) error {
+	var (
+		multiErr     error
+		measurements []string
+		resources    []string
+		processes    = map[string][]string{}
+	)
+
+	entries, ok := pluginConfig.([]interface{})
+	if !ok {
+		entries = []interface{}{pluginConfig}
+	}
+	for _, entry := range entries {
+		entryConfig, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		measurements = append(measurements, measurementNames(entryConfig["measurement"])...)
+		resources = append(resources, stringValues(entryConfig["resources"])...)
+		for _, key := range []string{"exe", "pattern", "pid_file"} {
+			if value, ok := entryConfig[key].(string); ok {
+				processes[key] = append(processes[key], value)
+			}
+		}
+	}
+
+	measurement := strings.TrimPrefix(metric.MetricName, plugin+"_")
+	if len(measurements) > 0 && !slices.Contains(measurements, measurement) && !slices.Contains(measurements, metric.MetricName) {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("metric %s is not collected since %s measurement %s is not in the agent config %v", metric.MetricName, plugin, measurement, measurements))
+	}
+
+	matchResource := len(resources) == 0 || slices.Contains(resources, "*") || len(metric.MetricDimension) == 0
+	for _, dimension := range metric.MetricDimension {
+		if slices.Contains(resources, dimension.Value) {
+			matchResource = true
+		}
+		if values, ok := processes[dimension.Name]; ok && !slices.Contains(values, dimension.Value) {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("metric %s has dimension %s=%s but %s only monitors %s %v", metric.MetricName, dimension.Name, dimension.Value, plugin, dimension.Name, values))
+		}
+	}
+	if !matchResource {
+		multiErr = multierr.Append(multiErr, fmt.Errorf("metric %s has no dimension value matching the %s resources in the agent config %v", metric.MetricName, plugin, resources))
+	}
+	return multiErr
+}
+
+// lintLogValidation checks the log streams in log_validation are published by the CloudWatchAgent config
+func lintLogValidation(vConfig validatorConfig, agentConfig map[string]interface{}) error {
+	// Log streams for the fluent receiver are configured in Fluent Bit instead of the agent config
+	if len(vConfig.LogValidation) == 0 || slices.Contains(vConfig.Receivers, "fluent") {
+		return nil
+	}
+
+	var logStreams []string
+	for _, collector := range []string{"files", "windows_events"} {
+		collectList, _ := lookup(agentConfig, "logs", "logs_collected", collector, "collect_list")
+		entries, _ := collectList.([]interface{})
+		for _, entry := range entries {
+			if entryConfig, ok := entry.(map[string]interface{}); ok {
+				if logStream, ok := entryConfig["log_stream_name"].(string); ok {
+					logStreams = append(logStreams, logStream)
+				}
+			}
+		}
+	}
+
+	var multiErr error
+	reported := map[string]bool{}
+	for _, logValidation := range vConfig.LogValidation {
+		if logValidation.LogStream == "" || reported[logValidation.LogStream] || matchLogStream(logStreams, logValidation.LogStream) {
+			continue
+		}
+		reported[logValidation.LogStream] = true
+		multiErr = multierr.Append(multiErr, fmt.Errorf("log stream %s in log_validation is not monitored by the agent config, monitored log streams are %v", logValidation.LogStream, logStreams))
+	}
+	return multiErr
+}
+
+func readAgentConfig(agentConfigPath string) (map[string]interface{}, error) {
+	agentConfigBytes, err := os.ReadFile(agentConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the agent config: %w", err)
+	}
+	var agentConfig map[string]interface{}
+	if err = json.Unmarshal(agentConfigBytes, &agentConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the agent config %s: %w", agentConfigPath, err)
+	}
+	return agentConfig, nil
+}
+
+// agentNamespaces returns every metric namespace the CloudWatchAgent config publishes to
+func agentNamespaces(agentConfig map[string]interface{}) []string {
+	var namespaces []string
+	if metrics, ok := agentConfig["metrics"].(map[string]interface{}); ok {
+		namespace, ok := metrics["namespace"].(string)
+		if !ok {
+			namespace = defaultMetricNamespace
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	// Namespaces of metrics extracted from logs (e.g prometheus emf_processor metric_namespace)
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if namespace, ok := child.(string); ok && key == "metric_namespace" {
+					namespaces = append(namespaces, namespace)
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(agentConfig["logs"])
+	sort.Strings(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// metricPlugin returns the metrics_collected plugin producing the metric name with the longest matching prefix
+// (e.g procstat for procstat_cpu_usage) or an empty string if no plugin matches
+func metricPlugin(plugins map[string]interface{}, metricName string) string {
+	var plugin string
+	for name := range plugins {
+		if strings.HasPrefix(metricName, name+"_") && len(name) > len(plugin) {
+			plugin = name
+		}
+	}
+	return plugin
+}
+
+// measurementNames supports both a measurement name and a measurement object with name and rename
+func measurementNames(value interface{}) []string {
+	var names []string
+	entries, _ := value.([]interface{})
+	for _, entry := range entries {
+		switch measurement := entry.(type) {
+		case string:
+			names = append(names, measurement)
+		case map[string]interface{}:
+			for _, key := range []string{"name", "rename"} {
+				if name, ok := measurement[key].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func stringValues(value interface{}) []string {
+	var values []string
+	entries, _ := value.([]interface{})
+	for _, entry := range entries {
+		if s, ok := entry.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// matchLogStream treats log stream names with placeholders (e.g {instance_id}) as matching any log stream
+func matchLogStream(logStreams []string, logStream string) bool {
+	for _, monitored := range logStreams {
+		if monitored == logStream || strings.Contains(monitored, "{") {
+			return true
+		}
+	}
+	return false
+}
+
+func lookup(config map[string]interface{}, keys ...string) (interface{}, bool) {
+	var value interface{} = config
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func isPlaceholder(value string) bool {
+	return strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">")
+}
@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qri-io/jsonschema"
+	"golang.org/x/exp/maps"
+)
+
+// ValidatorConstructor creates the validator for a validate_type in the validator yaml
+type ValidatorConstructor func(vConfig ValidateConfig) ValidatorFactory
+
+// LoadGeneratorConfig is the information from the validator that every receiver load generator needs
+type LoadGeneratorConfig struct {
+	Receiver        string
+	SendingInterval time.Duration
+	InstanceId      string
+}
+
+// LoadGenerator sends the metrics/logs/traces load for a receiver to CloudWatchAgent
+// (e.g sending 1000 statsd metrics per minute). It returns once the agent collection period has elapsed or the
+// context is done.
+type LoadGenerator func(ctx context.Context, vConfig ValidateConfig, cfg LoadGeneratorConfig) error
+
+// ValidatorRegistration describes a validate_type (e.g performance, stress)
+type ValidatorRegistration struct {
+	Name string
+	// ConfigSchema is an optional JSON schema that the validator yaml needs to satisfy for this validate type
+	ConfigSchema string
+	Factory      ValidatorConstructor
+}
+
+// ReceiverRegistration describes a receiver the validator can generate load for (e.g statsd, otlp)
+type ReceiverRegistration struct {
+	Name string
+	// ConfigSchema is an optional JSON schema that the validator yaml needs to satisfy when the receiver is used
+	ConfigSchema string
+	// LoadGenerator is nil for receivers that do not need any load since the agent collects the data itself (e.g system)
+	LoadGenerator LoadGenerator
+	// AgentConfigSections are the dotted paths in the CloudWatchAgent config that collect the receiver's data
+	// (e.g metrics.metrics_collected.statsd). The dry run fails if none of them is configured.
+	AgentConfigSections []string
+}
+
+var (
+	registryLock sync.RWMutex
+	validators   = map[string]ValidatorRegistration{}
+	receivers    = map[string]ReceiverRegistration{}
+)
+
+// RegisterValidator makes a validate type available to the validator yaml. It is meant to be called from the
+// init function of the package implementing the validator and panics if the name is already registered.
+func RegisterValidator(registration ValidatorRegistration) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if registration.Factory == nil {
+		panic(fmt.Sprintf("validator %s is registered without a factory", registration.Name))
+	}
+	if _, ok := validators[registration.Name]; ok {
+		panic(fmt.Sprintf("validator %s is already registered", registration.Name))
+	}
+	validators[registration.Name] = registration
+}
+
+// RegisterReceiver makes a receiver available to the validator yaml. It is meant to be called from the
+// init function of the package implementing the load generator and panics if the name is already registered.
+func RegisterReceiver(registration ReceiverRegistration) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := receivers[registration.Name]; ok {
+		panic(fmt.Sprintf("receiver %s is already registered", registration.Name))
+	}
+	receivers[registration.Name] = registration
+}
+
+// GetValidator returns the registered validate type or an error listing the available validate types
+func GetValidator(name string) (ValidatorRegistration, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	registration, ok := validators[name]
+	if !ok {
+		return ValidatorRegistration{}, fmt.Errorf("unknown validate type %q, available validate types are %v", name, sortedKeys(validators))
+	}
+	return registration, nil
+}
+
+// GetReceiver returns the registered receiver or an error listing the available receivers
+func GetReceiver(name string) (ReceiverRegistration, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	registration, ok := receivers[name]
+	if !ok {
+		return ReceiverRegistration{}, fmt.Errorf("unknown receiver %q, available receivers are %v", name, sortedKeys(receivers))
+	}
+	return registration, nil
+}
+
+// RegisteredValidators returns the names of every registered validate type
+func RegisteredValidators() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return sortedKeys(validators)
+}
+
+// RegisteredReceivers returns the names of every registered receiver
+func RegisteredReceivers() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return sortedKeys(receivers)
+}
+
+// validateSchema validates the raw validator yaml document against a registration's JSON schema
+func validateSchema(name, schema string, document map[string]interface{}) error {
+	if schema == "" {
+		return nil
+	}
+
+	rs := &jsonschema.Schema{}
+	if err := json.Unmarshal([]byte(schema), rs); err != nil {
+		return fmt.Errorf("invalid config schema for %s: %w", name, err)
+	}
+
+	documentBytes, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	keyErrors, err := rs.ValidateBytes(context.Background(), documentBytes)
+	if err != nil {
+		return fmt.Errorf("failed to execute config schema validation for %s: %w", name, err)
+	}
+	if len(keyErrors) > 0 {
+		return fmt.Errorf("validator config does not satisfy the config schema for %s: %v", name, keyErrors)
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
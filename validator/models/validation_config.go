@@ -11,11 +11,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v3"
 )
 
-var supportedReceivers = []string{"logs", "statsd", "collectd", "system", "emf", "xray", "app_signals", "traces"}
 var retryCount = 0
 
 type ValidateConfig interface {
@@ -25,11 +23,16 @@ type ValidateConfig interface {
 	GetDataType() string
 	GetNumberMonitoredLogs() int
 	GetDataRate() int
+	GetCardinality() int
 	GetCloudWatchAgentConfigPath() string
 	GetAgentCollectionPeriod() time.Duration
 	GetMetricNamespace() string
 	GetMetricValidation() []MetricValidation
 	GetLogValidation() []LogValidation
+	GetFluentForwardConfig() FluentForwardConfig
+	GetIngestionProbe() bool
+	GetLogIntegrity() bool
+	GetMeasuredService() string
 	GetCommitInformation() (string, int64)
 	GetUniqueID() string
 	GetOSFamily() string
@@ -45,6 +48,7 @@ type validatorConfig struct {
 	DataType              string `yaml:"data_type"`               // Only supports metrics/logs/traces
 	NumberMonitoredLogs   int    `yaml:"number_monitored_logs"`   // Number of logs to be monitored
 	ValuesPerMinute       string `yaml:"values_per_minute"`       // Number of metrics to be sent or number of log lines to write
+	Cardinality           int    `yaml:"cardinality"`             // Number of unique dimension sets to be sent for every metric (only used by otlp)
 	AgentCollectionPeriod int    `yaml:"agent_collection_period"` // Number of seconds the agent should run and collect the metrics
 	OSFamily              string `yaml:"os_family"`               // OS Family for the validator test
 
@@ -54,9 +58,19 @@ type validatorConfig struct {
 	MetricValidation []MetricValidation `yaml:"metric_validation"`
 	LogValidation    []LogValidation    `yaml:"log_validation"`
 
+	FluentForward FluentForwardConfig `yaml:"fluent_forward"` // Forward protocol options for the fluent receiver
+
+	IngestionProbe bool `yaml:"ingestion_probe"` // Send sentinel metrics to measure the metric ingestion latency (only used by statsd/collectd/emf)
+	LogIntegrity   bool `yaml:"log_integrity"`   // Number the log lines to check their delivery and latency (only used by logs)
+
+	MeasuredService string `yaml:"measured_service"` // Service the performance metrics are stored under, the agent when empty
+
 	CommitHash string `yaml:"commit_hash"`
 	CommitDate string `yaml:"commit_date"`
 	retryCount int
+
+	// document is the raw validator yaml used to validate the config schemas of the validate type and receivers
+	document map[string]interface{}
 }
 
 type MetricValidation struct {
@@ -68,12 +82,20 @@ type MetricValidation struct {
 
 type LogValidation struct {
 	LogValue  string `yaml:"log_value"`
-	LogLines  int    `yaml:"log_lines"`
+	LogLines  string `yaml:"log_lines"` // Number of log lines, a string like values_per_minute so it can be a placeholder
 	LogStream string `yaml:"log_stream"`
 	LogLevel  string `yaml:"log_level"`
 	LogSource string `yaml:"log_source"`
 }
 
+type FluentForwardConfig struct {
+	Address    string `yaml:"address"`     // Forward input address (e.g 127.0.0.1:24224)
+	Tag        string `yaml:"tag"`         // Fluent tag for every log event
+	Mode       string `yaml:"mode"`        // Only supports message/forward/packed_forward
+	RequireAck bool   `yaml:"require_ack"` // Require the forward input to acknowledge every message
+	BatchSize  int    `yaml:"batch_size"`  // Number of log events in each forward and packed_forward message
+}
+
 type MetricDimension struct {
 	Name  string `yaml:"name"`
 	Value string `yaml:"value"`
@@ -92,6 +114,10 @@ func NewValidateConfig(configPath string) (*validatorConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = yaml.Unmarshal(configPathBytes, &vConfig.document)
+	if err != nil {
+		return nil, err
+	}
 	log.Printf("Parameters validation for %v", vConfig)
 
 	if err := ValidateValidatorConfig(vConfig); err != nil {
@@ -100,10 +126,24 @@ func NewValidateConfig(configPath string) (*validatorConfig, error) {
 	return &vConfig, nil
 }
 
+// ValidateValidatorConfig checks the validate type and receivers are registered and the validator yaml
+// satisfies their config schemas
 func ValidateValidatorConfig(vConfig validatorConfig) error {
-	for _, receiver := range vConfig.Receivers {
-		if !slices.Contains(supportedReceivers, receiver) {
-			return fmt.Errorf("only support %v, the validator does not support %s", supportedReceivers, receiver)
+	validator, err := GetValidator(vConfig.ValidateType)
+	if err != nil {
+		return err
+	}
+	if err = validateSchema(validator.Name, validator.ConfigSchema, vConfig.document); err != nil {
+		return err
+	}
+
+	for _, name := range vConfig.Receivers {
+		receiver, err := GetReceiver(name)
+		if err != nil {
+			return err
+		}
+		if err = validateSchema(receiver.Name, receiver.ConfigSchema, vConfig.document); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -137,6 +177,22 @@ func (v *validatorConfig) GetDataRate() int {
 	return 0
 }
 
+// GetLogLines returns the number of log lines expected with the log value
+func (l LogValidation) GetLogLines() int {
+	if logLines, err := strconv.Atoi(l.LogLines); err == nil {
+		return logLines
+	}
+	return 0
+}
+
+// GetCardinality returns number of unique dimension sets to be sent for every generated metric
+func (v *validatorConfig) GetCardinality() int {
+	if v.Cardinality < 1 {
+		return 1
+	}
+	return v.Cardinality
+}
+
 // GetNumberMonitoredLogs returns number of log to be monitored by cloudwatchagent so the validator configuration will setup the agent config dynamically
 func (v *validatorConfig) GetNumberMonitoredLogs() int {
 	return v.NumberMonitoredLogs
@@ -167,6 +223,27 @@ func (v *validatorConfig) GetLogValidation() []LogValidation {
 	return v.LogValidation
 }
 
+// GetFluentForwardConfig returns the forward protocol options used to send logs to the fluent receiver
+func (v *validatorConfig) GetFluentForwardConfig() FluentForwardConfig {
+	return v.FluentForward
+}
+
+// GetIngestionProbe returns whether the metric generators send sentinel metrics to measure the ingestion latency
+func (v *validatorConfig) GetIngestionProbe() bool {
+	return v.IngestionProbe
+}
+
+// GetLogIntegrity returns whether the log generator numbers the log lines to check their delivery. The numbered
+// log lines are larger than the plain ones, so the throughput tests keep the plain log lines.
+func (v *validatorConfig) GetLogIntegrity() bool {
+	return v.LogIntegrity
+}
+
+// GetMeasuredService returns the service whose process the performance test measures, empty for the agent
+func (v *validatorConfig) GetMeasuredService() string {
+	return v.MeasuredService
+}
+
 func (v *validatorConfig) GetCommitInformation() (string, int64) {
 	commitDate, _ := strconv.ParseInt(v.CommitDate, 10, 64)
 	return v.CommitHash, commitDate
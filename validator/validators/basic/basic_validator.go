@@ -4,6 +4,7 @@
 package basic
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -16,8 +17,6 @@ import (
 
 	AppSignalMetrics "github.com/aws/amazon-cloudwatch-agent-test/test/metric"
 	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
-	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
-	"github.com/aws/amazon-cloudwatch-agent-test/util/common/traces"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/models"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/util"
 )
@@ -37,30 +36,32 @@ func NewBasicValidator(vConfig models.ValidateConfig) models.ValidatorFactory {
 	}
 }
 
-func (s *BasicValidator) GenerateLoad() error {
+func (s *BasicValidator) GenerateLoad(ctx context.Context) error {
 	var (
 		metricSendingInterval = time.Minute
-		logGroup              = awsservice.GetInstanceId()
-		metricNamespace       = s.vConfig.GetMetricNamespace()
-		dataRate              = s.vConfig.GetDataRate()
-		dataType              = s.vConfig.GetDataType()
-		agentCollectionPeriod = s.vConfig.GetAgentCollectionPeriod()
-		agentConfigFilePath   = s.vConfig.GetCloudWatchAgentConfigPath()
-		receiver              = s.vConfig.GetPluginsConfig()[0]
+		instanceId            = awsservice.GetInstanceId()
+		name                  = s.vConfig.GetPluginsConfig()[0]
 	)
 
-	switch dataType {
-	case "logs":
-		return common.StartLogWrite(agentConfigFilePath, agentCollectionPeriod, metricSendingInterval, dataRate)
-	case "traces":
-		return traces.StartTraceGeneration(receiver, agentConfigFilePath, agentCollectionPeriod, metricSendingInterval)
-	default:
-		// Sending metrics based on the receivers; however, for scraping plugin  (e.g prometheus), we would need to scrape it instead of sending
-		return common.StartSendingMetrics(receiver, agentCollectionPeriod, metricSendingInterval, dataRate, logGroup, metricNamespace)
+	receiver, err := models.GetReceiver(name)
+	if err != nil {
+		return err
 	}
+
+	// Scraping plugins (e.g prometheus) and host metrics (e.g system) are collected by the agent itself
+	// and do not register any load generator
+	if receiver.LoadGenerator == nil {
+		return nil
+	}
+
+	return receiver.LoadGenerator(ctx, s.vConfig, models.LoadGeneratorConfig{
+		Receiver:        receiver.Name,
+		SendingInterval: metricSendingInterval,
+		InstanceId:      instanceId,
+	})
 }
 
-func (s *BasicValidator) CheckData(startTime, endTime time.Time) error {
+func (s *BasicValidator) CheckData(ctx context.Context, startTime, endTime time.Time) error {
 	var (
 		multiErr         error
 		ec2InstanceId    = awsservice.GetInstanceId()
@@ -97,7 +98,7 @@ func (s *BasicValidator) CheckData(startTime, endTime time.Time) error {
 				fmt.Println("App Signal Metrics are correct!")
 			}
 		} else {
-			err := s.ValidateMetric(metric.MetricName, metricNamespace, metricDimensions, metric.MetricValue, metric.MetricSampleCount, startTime, endTime)
+			err := s.ValidateMetric(ctx, metric.MetricName, metricNamespace, metricDimensions, metric.MetricValue, metric.MetricSampleCount, startTime, endTime)
 			if err != nil {
 				return err
 			}
@@ -111,7 +112,7 @@ func (s *BasicValidator) CheckData(startTime, endTime time.Time) error {
 		fmt.Println("Traces Metrics are correct!")
 	}
 	for _, logValidation := range logValidations {
-		err := s.ValidateLogs(logValidation.LogStream, logValidation.LogValue, logValidation.LogLevel, logValidation.LogSource, logValidation.LogLines, startTime, endTime)
+		err := s.ValidateLogs(ctx, logValidation.LogStream, logValidation.LogValue, logValidation.LogLevel, logValidation.LogSource, logValidation.GetLogLines(), startTime, endTime)
 		if err != nil {
 			multiErr = multierr.Append(multiErr, err)
 		}
@@ -179,10 +180,11 @@ func (s *BasicValidator) ValidateTracesMetrics() error {
 	return nil
 }
 
-func (s *BasicValidator) ValidateLogs(logStream, logLine, logLevel, logSource string, expectedMinimumEventCount int, startTime, endTime time.Time) error {
+func (s *BasicValidator) ValidateLogs(ctx context.Context, logStream, logLine, logLevel, logSource string, expectedMinimumEventCount int, startTime, endTime time.Time) error {
 	logGroup := awsservice.GetInstanceId()
 	log.Printf("Start to validate that substring '%s' has at least %d log event(s) within log group %s, log stream %s, between %v and %v", logLine, expectedMinimumEventCount, logGroup, logStream, startTime, endTime)
-	return awsservice.ValidateLogs(
+	return awsservice.ValidateLogsWithContext(
+		ctx,
 		logGroup,
 		logStream,
 		&startTime,
@@ -212,7 +214,7 @@ func (s *BasicValidator) ValidateLogs(logStream, logLine, logLevel, logSource st
 	)
 }
 
-func (s *BasicValidator) ValidateMetric(metricName, metricNamespace string, metricDimensions []cwtypes.Dimension, metricValue float64, metricSampleCount int, startTime, endTime time.Time) error {
+func (s *BasicValidator) ValidateMetric(ctx context.Context, metricName, metricNamespace string, metricDimensions []cwtypes.Dimension, metricValue float64, metricSampleCount int, startTime, endTime time.Time) error {
 	var (
 		boundAndPeriod = s.vConfig.GetAgentCollectionPeriod().Seconds()
 	)
@@ -221,7 +223,7 @@ func (s *BasicValidator) ValidateMetric(metricName, metricNamespace string, metr
 
 	log.Printf("Start to collect and validate metric %s with the namespace %s, start time %v and end time %v \n", metricName, metricNamespace, startTime, endTime)
 
-	metrics, err := awsservice.GetMetricData(metricQueries, startTime, endTime)
+	metrics, err := awsservice.GetMetricDataWithContext(ctx, metricQueries, startTime, endTime)
 	if err != nil {
 		return err
 	}
@@ -232,7 +234,7 @@ func (s *BasicValidator) ValidateMetric(metricName, metricNamespace string, metr
 
 	// Validate if the metrics are not dropping any metrics and able to backfill within the same minute (e.g if the memory_rss metric is having collection_interval 1
 	// , it will need to have 60 sample counts - 1 datapoint / second)
-	if ok := awsservice.ValidateSampleCount(metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount, metricSampleCount, int32(boundAndPeriod)); !ok {
+	if ok := awsservice.ValidateSampleCountWithContext(ctx, metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount, metricSampleCount, int32(boundAndPeriod)); !ok {
 		return fmt.Errorf("\n metric %s is not within sample count bound [ %d, %d]", metricName, metricSampleCount, metricSampleCount)
 	}
 
@@ -4,6 +4,7 @@
 package feature
 
 import (
+	"context"
 	"time"
 
 	"go.uber.org/multierr"
@@ -21,6 +22,13 @@ type FeatureValidator struct {
 
 var _ models.ValidatorFactory = (*FeatureValidator)(nil)
 
+func init() {
+	models.RegisterValidator(models.ValidatorRegistration{
+		Name:    "feature",
+		Factory: NewFeatureValidator,
+	})
+}
+
 func NewFeatureValidator(vConfig models.ValidateConfig) models.ValidatorFactory {
 	return &FeatureValidator{
 		vConfig:          vConfig,
@@ -28,12 +36,12 @@ func NewFeatureValidator(vConfig models.ValidateConfig) models.ValidatorFactory
 	}
 }
 
-func (s *FeatureValidator) GenerateLoad() error {
+// GenerateLoad writes the monitored logs and sends the load of every receiver at the same time
+func (s *FeatureValidator) GenerateLoad(ctx context.Context) error {
 	var (
 		multiErr              error
 		metricSendingInterval = time.Minute
-		logGroup              = awsservice.GetInstanceId()
-		metricNamespace       = s.vConfig.GetMetricNamespace()
+		instanceId            = awsservice.GetInstanceId()
 		dataRate              = s.vConfig.GetDataRate()
 		agentCollectionPeriod = s.vConfig.GetAgentCollectionPeriod()
 		agentConfigFilePath   = s.vConfig.GetCloudWatchAgentConfigPath()
@@ -41,16 +49,35 @@ func (s *FeatureValidator) GenerateLoad() error {
 		validationLog         = s.vConfig.GetLogValidation()
 	)
 
-	if err := common.GenerateLogs(agentConfigFilePath, agentCollectionPeriod, metricSendingInterval, dataRate, validationLog); err != nil {
-		multiErr = multierr.Append(multiErr, err)
+	loadGenerators := []func() error{
+		func() error {
+			return common.GenerateLogs(ctx, agentConfigFilePath, agentCollectionPeriod, metricSendingInterval, dataRate, validationLog)
+		},
 	}
 
 	// Sending metrics based on the receivers; however, for scraping plugin  (e.g prometheus), we would need to scrape it instead of sending
-	for _, receiver := range receivers {
-		if err := common.StartSendingMetrics(receiver, agentCollectionPeriod, metricSendingInterval, dataRate, logGroup, metricNamespace); err != nil {
+	for _, name := range receivers {
+		// The monitored log files are written by GenerateLogs
+		if name == "logs" {
+			continue
+		}
+		receiver, err := models.GetReceiver(name)
+		if err != nil {
 			multiErr = multierr.Append(multiErr, err)
+			continue
+		}
+		if receiver.LoadGenerator == nil {
+			continue
+		}
+		loadConfig := models.LoadGeneratorConfig{
+			Receiver:        receiver.Name,
+			SendingInterval: metricSendingInterval,
+			InstanceId:      instanceId,
 		}
+		loadGenerators = append(loadGenerators, func() error {
+			return receiver.LoadGenerator(ctx, s.vConfig, loadConfig)
+		})
 	}
 
-	return multiErr
+	return multierr.Append(multiErr, common.RunConcurrently(loadGenerators...))
 }
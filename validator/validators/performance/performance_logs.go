@@ -0,0 +1,88 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package performance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.uber.org/multierr"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/logs/integrity"
+	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/basic"
+)
+
+// LogDeliveryLatency is stored next to the procstat metrics with the delay in milliseconds from writing a log line
+// to its ingestion by CloudWatch Logs
+const LogDeliveryLatency = "log_delivery_latency"
+
+// GetLogDeliveryStats pulls the log events written by the validator back from CloudWatch Logs and returns the log
+// delivery latency statistics. The lost, duplicated and out of order log lines of every log file fail the test.
+func (s *PerformanceValidator) GetLogDeliveryStats(ctx context.Context, startTime time.Time) (Stats, error) {
+	var (
+		tracker               = common.LogIntegrityTracker()
+		logGroup              = awsservice.GetInstanceId()
+		fileIDs               = tracker.FileIDs()
+		agentCollectionPeriod = s.vConfig.GetAgentCollectionPeriod().Seconds()
+		latencies             []float64
+		multiErr              error
+	)
+
+	if len(fileIDs) == 0 {
+		return Stats{}, fmt.Errorf("no log lines have been written for run %s", tracker.RunID())
+	}
+
+	for _, fileID := range fileIDs {
+		var report integrity.Report
+		// The log stream name is the log file name in the generated agent config
+		err := awsservice.ValidateLogsWithContext(ctx, logGroup, fileID, &startTime, nil, func(events []types.OutputLogEvent) error {
+			report = tracker.Check(fileID, events)
+			return nil
+		})
+		if err != nil {
+			multiErr = multierr.Append(multiErr, err)
+			continue
+		}
+
+		log.Printf("Log delivery for run %s %v", tracker.RunID(), report)
+		if report.Lost > 0 || report.Duplicated > 0 || report.OutOfOrder > 0 {
+			multiErr = multierr.Append(multiErr, fmt.Errorf("log delivery for run %s failed for %v", tracker.RunID(), report))
+		}
+		for _, latency := range report.Latencies {
+			latencies = append(latencies, float64(latency.Milliseconds()))
+		}
+	}
+
+	if multiErr != nil {
+		return Stats{}, multiErr
+	}
+	if len(latencies) == 0 {
+		return Stats{}, fmt.Errorf("no log lines of run %s have been delivered to log group %s", tracker.RunID(), logGroup)
+	}
+	return CalculateMetricStatisticsBasedOnDataAndPeriod(latencies, agentCollectionPeriod), nil
+}
+
+// ValidateLogs checks the log_validation of the validator config the way the feature tests do, so the performance
+// tests of the load generators writing to CloudWatch Logs through another log forwarder check the delivered events
+func (s *PerformanceValidator) ValidateLogs(ctx context.Context, startTime, endTime time.Time) error {
+	logValidations := s.vConfig.GetLogValidation()
+	if len(logValidations) == 0 {
+		return nil
+	}
+	basicValidator, ok := s.ValidatorFactory.(*basic.BasicValidator)
+	if !ok {
+		return fmt.Errorf("performance validator cannot validate logs with %T", s.ValidatorFactory)
+	}
+	var multiErr error
+	for _, logValidation := range logValidations {
+		err := basicValidator.ValidateLogs(ctx, logValidation.LogStream, logValidation.LogValue, logValidation.LogLevel, logValidation.LogSource, logValidation.GetLogLines(), startTime, endTime)
+		multiErr = multierr.Append(multiErr, err)
+	}
+	return multiErr
+}
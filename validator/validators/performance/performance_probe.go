@@ -0,0 +1,129 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/awsservice"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/metrics/probe"
+)
+
+const (
+	// MetricIngestionLatency is stored next to the procstat metrics with the delay in milliseconds from sending
+	// a sentinel metric to the metric being queryable in CloudWatch
+	MetricIngestionLatency = "metric_ingestion_latency"
+	probePollInterval      = 10 * time.Second
+
+	// The agent flushes the metrics every minute and the logs (including emf) every 5 seconds by default
+	defaultMetricsFlushInterval = 60
+	defaultLogsFlushInterval    = 5
+)
+
+// GenerateLoad starts polling CloudWatch for the sentinel metrics before generating the load when the
+// ingestion probe is enabled. The polling outlives the load generation, whose context is done as soon as the load
+// has been sent, and is stopped by GetMetricIngestionStats, a load generation failure or Cleanup.
+func (s *PerformanceValidator) GenerateLoad(ctx context.Context) error {
+	if !s.vConfig.GetIngestionProbe() {
+		return s.ValidatorFactory.GenerateLoad(ctx)
+	}
+
+	// The sentinels of a previous validation attempt are not part of this one
+	s.stopIngestionProbe()
+	recorder := common.MetricIngestionProbe()
+	recorder.Reset()
+	probeCtx, cancel := context.WithCancel(context.Background())
+	s.stopProbe = cancel
+	go recorder.Poll(probeCtx, s.vConfig.GetMetricNamespace(), awsservice.GetInstanceId(), probePollInterval)
+
+	err := s.ValidatorFactory.GenerateLoad(ctx)
+	if err != nil {
+		s.stopIngestionProbe()
+	}
+	return err
+}
+
+// Cleanup stops polling for the sentinel metrics when the validation ends before their stats are calculated
+func (s *PerformanceValidator) Cleanup() error {
+	s.stopIngestionProbe()
+	return s.ValidatorFactory.Cleanup()
+}
+
+func (s *PerformanceValidator) stopIngestionProbe() {
+	if s.stopProbe != nil {
+		s.stopProbe()
+		s.stopProbe = nil
+	}
+}
+
+// GetMetricIngestionStats stops polling for the sentinel metrics, logs the ingestion latency percentiles per
+// receiver and flush interval and returns the ingestion latency statistics
+func (s *PerformanceValidator) GetMetricIngestionStats() (Stats, error) {
+	s.stopIngestionProbe()
+
+	sentinels := common.MetricIngestionProbe().Sentinels()
+	if len(sentinels) == 0 {
+		return Stats{}, fmt.Errorf("no sentinel metric has been sent with the ingestion probe enabled")
+	}
+
+	flushIntervals, err := agentFlushIntervals(s.vConfig.GetCloudWatchAgentConfigPath())
+	if err != nil {
+		return Stats{}, err
+	}
+	for receiver, percentiles := range probe.Summarize(sentinels) {
+		log.Printf("Metric ingestion latency for receiver %s with flush interval %ds: %v", receiver, flushIntervals[receiver], percentiles)
+	}
+
+	var latencies []float64
+	for _, sentinel := range sentinels {
+		if !sentinel.QueryableTime.IsZero() {
+			latencies = append(latencies, float64(sentinel.Latency().Milliseconds()))
+		}
+	}
+	if len(latencies) == 0 {
+		return Stats{}, fmt.Errorf("none of the %d sentinel metrics became queryable", len(sentinels))
+	}
+	return CalculateMetricStatisticsBasedOnDataAndPeriod(latencies, s.vConfig.GetAgentCollectionPeriod().Seconds()), nil
+}
+
+// agentFlushIntervals returns the force_flush_interval in seconds applying to the metrics of every probed receiver
+func agentFlushIntervals(agentConfigPath string) (map[string]int, error) {
+	agentConfigBytes, err := os.ReadFile(agentConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var agentConfig struct {
+		Metrics struct {
+			ForceFlushInterval *int `json:"force_flush_interval"`
+		} `json:"metrics"`
+		Logs struct {
+			ForceFlushInterval *int `json:"force_flush_interval"`
+		} `json:"logs"`
+	}
+	if err = json.Unmarshal(agentConfigBytes, &agentConfig); err != nil {
+		return nil, err
+	}
+
+	metricsFlushInterval, logsFlushInterval := defaultMetricsFlushInterval, defaultLogsFlushInterval
+	if agentConfig.Metrics.ForceFlushInterval != nil {
+		metricsFlushInterval = *agentConfig.Metrics.ForceFlushInterval
+	}
+	if agentConfig.Logs.ForceFlushInterval != nil {
+		logsFlushInterval = *agentConfig.Logs.ForceFlushInterval
+	}
+
+	// EMF metrics are sent as structured logs
+	return map[string]int{
+		"statsd":   metricsFlushInterval,
+		"collectd": metricsFlushInterval,
+		"emf":      logsFlushInterval,
+	}, nil
+}
@@ -4,6 +4,7 @@
 package performance
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -26,6 +27,17 @@ const (
 	DynamoDBDataBase = "CWAPerformanceMetrics"
 )
 
+// performanceConfigSchema requires a single receiver since the performance metrics are recorded per use case
+const performanceConfigSchema = `{
+	"type": "object",
+	"required": ["receivers", "metric_namespace", "metric_validation"],
+	"properties": {
+		"receivers": {"type": "array", "minItems": 1, "maxItems": 1},
+		"metric_namespace": {"type": "string", "minLength": 1},
+		"metric_validation": {"type": "array", "minItems": 1}
+	}
+}`
+
 var (
 	// The default unit for these metrics is byte. However, we want to convert to MB for easier understanding
 	metricsConvertToMB = []string{"mem_total", "procstat_memory_rss", "procstat_memory_swap", "procstat_memory_data", "procstat_memory_vms", "procstat_write_bytes", "procstat_bytes_sent", "memory_rss", "memory_vms", "write_bytes", "Bytes_Sent_Per_Sec", "Available_Bytes"}
@@ -34,10 +46,20 @@ var (
 type PerformanceValidator struct {
 	vConfig models.ValidateConfig
 	models.ValidatorFactory
+	// stopProbe stops polling for the sentinel metrics of the ingestion probe
+	stopProbe context.CancelFunc
 }
 
 var _ models.ValidatorFactory = (*PerformanceValidator)(nil)
 
+func init() {
+	models.RegisterValidator(models.ValidatorRegistration{
+		Name:         "performance",
+		ConfigSchema: performanceConfigSchema,
+		Factory:      NewPerformanceValidator,
+	})
+}
+
 func NewPerformanceValidator(vConfig models.ValidateConfig) models.ValidatorFactory {
 	return &PerformanceValidator{
 		vConfig:          vConfig,
@@ -45,10 +67,10 @@ func NewPerformanceValidator(vConfig models.ValidateConfig) models.ValidatorFact
 	}
 }
 
-func (s *PerformanceValidator) CheckData(startTime, endTime time.Time) error {
+func (s *PerformanceValidator) CheckData(ctx context.Context, startTime, endTime time.Time) error {
 	perfInfo := PerformanceInformation{}
 	if s.vConfig.GetOSFamily() == "windows" {
-		stat, err := s.GetWindowsPerformanceMetrics(startTime, endTime)
+		stat, err := s.GetWindowsPerformanceMetrics(ctx, startTime, endTime)
 		if err != nil {
 			return err
 		}
@@ -57,7 +79,7 @@ func (s *PerformanceValidator) CheckData(startTime, endTime time.Time) error {
 			return err
 		}
 	} else {
-		metrics, err := s.GetPerformanceMetrics(startTime, endTime)
+		metrics, err := s.GetPerformanceMetrics(ctx, startTime, endTime)
 		if err != nil {
 			return err
 		}
@@ -67,6 +89,29 @@ func (s *PerformanceValidator) CheckData(startTime, endTime time.Time) error {
 			return err
 		}
 	}
+
+	// Store the log delivery and metric ingestion latencies next to the agent's cpu and memory stats
+	results := perfInfo["Results"].(map[string]interface{})[fmt.Sprint(s.vConfig.GetDataRate())].(map[string]Stats)
+	if slices.Contains(s.vConfig.GetPluginsConfig(), "logs") && s.vConfig.GetLogIntegrity() {
+		latency, err := s.GetLogDeliveryStats(ctx, startTime)
+		if err != nil {
+			return err
+		}
+		results[LogDeliveryLatency] = latency
+	}
+	if s.vConfig.GetIngestionProbe() {
+		latency, err := s.GetMetricIngestionStats()
+		if err != nil {
+			return err
+		}
+		results[MetricIngestionLatency] = latency
+	}
+
+	// The log events shipped by other log forwarders (e.g Fluent Bit) are not part of the log delivery stats
+	if err := s.ValidateLogs(ctx, startTime, endTime); err != nil {
+		return err
+	}
+
 	err := s.SendPacketToDatabase(perfInfo)
 	if err != nil {
 		return err
@@ -99,7 +144,7 @@ func (s *PerformanceValidator) SendPacketToDatabase(perfInfo PerformanceInformat
 		// and finally replace the packet in the database
 		maps.Copy(existingPerfInfo["Results"].(map[string]interface{}), perfInfo["Results"].(map[string]interface{}))
 
-		finalPerfInfo := packIntoPerformanceInformation(existingPerfInfo["UniqueID"].(string), receiver, dataType, agentCollectionPeriod, commitHash, commitDate, existingPerfInfo["Results"])
+		finalPerfInfo := packIntoPerformanceInformation(existingPerfInfo["UniqueID"].(string), s.serviceName(), receiver, dataType, agentCollectionPeriod, commitHash, commitDate, existingPerfInfo["Results"])
 
 		err = awsservice.ReplaceItemInDatabase(DynamoDBDataBase, finalPerfInfo)
 
@@ -141,7 +186,7 @@ func (s *PerformanceValidator) CalculateMetricStatsAndPackMetrics(metrics []type
 		performanceMetricResults[metricName] = metricStats
 	}
 
-	return packIntoPerformanceInformation(uniqueID, receiver, dataType, fmt.Sprint(agentCollectionPeriod), commitHash, commitDate, map[string]interface{}{dataRate: performanceMetricResults}), nil
+	return packIntoPerformanceInformation(uniqueID, s.serviceName(), receiver, dataType, fmt.Sprint(agentCollectionPeriod), commitHash, commitDate, map[string]interface{}{dataRate: performanceMetricResults}), nil
 }
 
 func (s *PerformanceValidator) CalculateWindowsMetricStatsAndPackMetrics(statistic []*cloudwatch.GetMetricStatisticsOutput) (PerformanceInformation, error) {
@@ -181,10 +226,10 @@ func (s *PerformanceValidator) CalculateWindowsMetricStatsAndPackMetrics(statist
 		performanceMetricResults[metricName] = metricStats
 	}
 
-	return packIntoPerformanceInformation(uniqueID, receiver, dataType, fmt.Sprint(agentCollectionPeriod), commitHash, commitDate, map[string]interface{}{dataRate: performanceMetricResults}), nil
+	return packIntoPerformanceInformation(uniqueID, s.serviceName(), receiver, dataType, fmt.Sprint(agentCollectionPeriod), commitHash, commitDate, map[string]interface{}{dataRate: performanceMetricResults}), nil
 }
 
-func (s *PerformanceValidator) GetPerformanceMetrics(startTime, endTime time.Time) ([]types.MetricDataResult, error) {
+func (s *PerformanceValidator) GetPerformanceMetrics(ctx context.Context, startTime, endTime time.Time) ([]types.MetricDataResult, error) {
 	var (
 		metricNamespace              = s.vConfig.GetMetricNamespace()
 		validationMetric             = s.vConfig.GetMetricValidation()
@@ -222,7 +267,7 @@ func (s *PerformanceValidator) GetPerformanceMetrics(startTime, endTime time.Tim
 			})
 		}
 	}
-	metrics, err := awsservice.GetMetricData(performanceMetricDataQueries, startTime, endTime)
+	metrics, err := awsservice.GetMetricDataWithContext(ctx, performanceMetricDataQueries, startTime, endTime)
 
 	if err != nil {
 		return nil, err
@@ -231,7 +276,7 @@ func (s *PerformanceValidator) GetPerformanceMetrics(startTime, endTime time.Tim
 	return metrics.MetricDataResults, nil
 }
 
-func (s *PerformanceValidator) GetWindowsPerformanceMetrics(startTime, endTime time.Time) ([]*cloudwatch.GetMetricStatisticsOutput, error) {
+func (s *PerformanceValidator) GetWindowsPerformanceMetrics(ctx context.Context, startTime, endTime time.Time) ([]*cloudwatch.GetMetricStatisticsOutput, error) {
 	var (
 		metricNamespace  = s.vConfig.GetMetricNamespace()
 		validationMetric = s.vConfig.GetMetricValidation()
@@ -259,7 +304,7 @@ func (s *PerformanceValidator) GetWindowsPerformanceMetrics(startTime, endTime t
 		}
 		// Windows procstat metrics always append a space and GetMetricData does not support space character
 		// Only workaround is to use GetMetricStatistics and retrieve the datapoints on a secondly period
-		statistic, err := awsservice.GetMetricStatistics(stat.MetricName, metricNamespace, metricDimensions, startTime, endTime, 1, statList, nil)
+		statistic, err := awsservice.GetMetricStatisticsWithContext(ctx, stat.MetricName, metricNamespace, metricDimensions, startTime, endTime, 1, statList, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -291,15 +336,24 @@ func (s *PerformanceValidator) buildPerformanceMetricQueries(metricName, metricN
 	return metricDataQuery
 }
 
+// serviceName returns the service the performance metrics are stored under, the agent unless the test measures
+// another process (e.g Fluent Bit) so its results do not mix into the agent baselines
+func (s *PerformanceValidator) serviceName() string {
+	if service := s.vConfig.GetMeasuredService(); service != "" {
+		return service
+	}
+	return ServiceName
+}
+
 // packIntoPerformanceInformation will package all the information into the required format of MongoDb Database
 // https://github.com/aws/amazon-cloudwatch-agent-test/blob/e07fe7adb1b1d75244d8984507d3f83a7237c3d3/terraform/setup/main.tf#L8-L63
-func packIntoPerformanceInformation(uniqueID, receiver, dataType, collectionPeriod, commitHash string, commitDate int64, result interface{}) PerformanceInformation {
+func packIntoPerformanceInformation(uniqueID, service, receiver, dataType, collectionPeriod, commitHash string, commitDate int64, result interface{}) PerformanceInformation {
 	instanceAMI := awsservice.GetImageId()
 	instanceType := awsservice.GetInstanceType()
 
 	return PerformanceInformation{
 		"UniqueID":         uniqueID,
-		"Service":          ServiceName,
+		"Service":          service,
 		"UseCase":          receiver,
 		"CommitDate":       commitDate,
 		"CommitHash":       commitHash,
@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package validators
+
+import (
+	"context"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/logs/integrity"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/metrics/probe"
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common/traces"
+	"github.com/aws/amazon-cloudwatch-agent-test/validator/models"
+)
+
+const otlpConfigSchema = `{
+	"properties": {
+		"cardinality": {"type": "integer", "minimum": 1}
+	}
+}`
+
+const fluentConfigSchema = `{
+	"properties": {
+		"fluent_forward": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"address": {"type": "string"},
+				"tag": {"type": "string"},
+				"mode": {"enum": ["message", "forward", "packed_forward"]},
+				"require_ack": {"type": "boolean"},
+				"batch_size": {"type": "integer", "minimum": 1}
+			}
+		}
+	}
+}`
+
+// The built-in receivers, whose load generators live in util/common
+func init() {
+	for receiver, agentConfigSection := range map[string]string{
+		"statsd":      "metrics.metrics_collected.statsd",
+		"collectd":    "metrics.metrics_collected.collectd",
+		"emf":         "logs.metrics_collected.emf",
+		"app_signals": "logs.metrics_collected.app_signals",
+		"traces":      "traces.traces_collected.app_signals",
+	} {
+		models.RegisterReceiver(models.ReceiverRegistration{
+			Name:                receiver,
+			LoadGenerator:       generateMetricsLoad,
+			AgentConfigSections: []string{agentConfigSection},
+		})
+	}
+	models.RegisterReceiver(models.ReceiverRegistration{
+		Name:                "otlp",
+		ConfigSchema:        otlpConfigSchema,
+		LoadGenerator:       generateMetricsLoad,
+		AgentConfigSections: []string{"metrics.metrics_collected.otlp"},
+	})
+	// Host metrics are collected by the agent itself
+	models.RegisterReceiver(models.ReceiverRegistration{Name: "system"})
+
+	models.RegisterReceiver(models.ReceiverRegistration{
+		Name:                "logs",
+		LoadGenerator:       generateLogsLoad,
+		AgentConfigSections: []string{"logs.logs_collected.files"},
+	})
+	// The forward input is configured in Fluent Bit instead of the CloudWatchAgent config
+	models.RegisterReceiver(models.ReceiverRegistration{
+		Name:         "fluent",
+		ConfigSchema: fluentConfigSchema,
+		LoadGenerator: func(ctx context.Context, vConfig models.ValidateConfig, cfg models.LoadGeneratorConfig) error {
+			return common.StartFluentForward(ctx, vConfig.GetFluentForwardConfig(), vConfig.GetAgentCollectionPeriod(), cfg.SendingInterval, vConfig.GetDataRate())
+		},
+	})
+
+	models.RegisterReceiver(models.ReceiverRegistration{
+		Name: "xray",
+		LoadGenerator: func(ctx context.Context, vConfig models.ValidateConfig, cfg models.LoadGeneratorConfig) error {
+			return traces.StartTraceGeneration(ctx, cfg.Receiver, vConfig.GetCloudWatchAgentConfigPath(), vConfig.GetAgentCollectionPeriod(), cfg.SendingInterval)
+		},
+		AgentConfigSections: []string{"traces.traces_collected.xray"},
+	})
+}
+
+func generateMetricsLoad(ctx context.Context, vConfig models.ValidateConfig, cfg models.LoadGeneratorConfig) error {
+	var ingestionProbe *probe.Recorder
+	if vConfig.GetIngestionProbe() {
+		ingestionProbe = common.MetricIngestionProbe()
+	}
+	return common.StartSendingMetrics(ctx, cfg.Receiver, vConfig.GetAgentCollectionPeriod(), cfg.SendingInterval, vConfig.GetDataRate(), vConfig.GetCardinality(), cfg.InstanceId, vConfig.GetMetricNamespace(), ingestionProbe)
+}
+
+func generateLogsLoad(ctx context.Context, vConfig models.ValidateConfig, cfg models.LoadGeneratorConfig) error {
+	var tracker *integrity.Tracker
+	if vConfig.GetLogIntegrity() {
+		tracker = common.LogIntegrityTracker()
+	}
+	return common.StartLogWrite(ctx, vConfig.GetCloudWatchAgentConfigPath(), vConfig.GetAgentCollectionPeriod(), cfg.SendingInterval, vConfig.GetDataRate(), tracker)
+}
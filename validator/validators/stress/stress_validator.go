@@ -4,6 +4,7 @@
 package stress
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -19,12 +20,25 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/util"
 )
 
+// stressConfigSchema requires a single receiver and a data rate since the bounds are defined per data rate and receiver
+const stressConfigSchema = `{
+	"type": "object",
+	"required": ["receivers", "values_per_minute", "metric_namespace", "metric_validation"],
+	"properties": {
+		"receivers": {"type": "array", "minItems": 1, "maxItems": 1},
+		"metric_namespace": {"type": "string", "minLength": 1},
+		"metric_validation": {"type": "array", "minItems": 1}
+	}
+}`
+
 type MetricPluginBoundValue map[string]map[string]map[string]float64
 
 // Todo:
 // * Create a database  to store these metrics instead of using cache?
 // * Create a workflow to update the bound metrics?
 // * Add more metrics (healthcheckextension to detect dropping metrics and prometheus to detect agent crash in EKS env)
+// * Add the otlp bounds once there is a baseline for the otlp receiver, then add test/stress/otlp to the ec2_stress
+//   matrix of the generator
 var (
 	metricErrorBound       = 0.3
 	metricPluginBoundValue = MetricPluginBoundValue{
@@ -323,6 +337,14 @@ type StressValidator struct {
 
 var _ models.ValidatorFactory = (*StressValidator)(nil)
 
+func init() {
+	models.RegisterValidator(models.ValidatorRegistration{
+		Name:         "stress",
+		ConfigSchema: stressConfigSchema,
+		Factory:      NewStressValidator,
+	})
+}
+
 func NewStressValidator(vConfig models.ValidateConfig) models.ValidatorFactory {
 	return &StressValidator{
 		vConfig:          vConfig,
@@ -330,7 +352,7 @@ func NewStressValidator(vConfig models.ValidateConfig) models.ValidatorFactory {
 	}
 }
 
-func (s *StressValidator) CheckData(startTime, endTime time.Time) error {
+func (s *StressValidator) CheckData(ctx context.Context, startTime, endTime time.Time) error {
 	var (
 		multiErr         error
 		ec2InstanceId    = awsservice.GetInstanceId()
@@ -353,9 +375,9 @@ func (s *StressValidator) CheckData(startTime, endTime time.Time) error {
 
 		var err error
 		if s.vConfig.GetOSFamily() == "windows" {
-			err = s.ValidateStressMetricWindows(metric.MetricName, metricNamespace, metricDimensions, metric.MetricSampleCount, startTime, endTime)
+			err = s.ValidateStressMetricWindows(ctx, metric.MetricName, metricNamespace, metricDimensions, metric.MetricSampleCount, startTime, endTime)
 		} else {
-			err = s.ValidateStressMetric(metric.MetricName, metricNamespace, metricDimensions, metric.MetricSampleCount, startTime, endTime)
+			err = s.ValidateStressMetric(ctx, metric.MetricName, metricNamespace, metricDimensions, metric.MetricSampleCount, startTime, endTime)
 		}
 		if err != nil {
 			multiErr = multierr.Append(multiErr, err)
@@ -365,7 +387,7 @@ func (s *StressValidator) CheckData(startTime, endTime time.Time) error {
 	return multiErr
 }
 
-func (s *StressValidator) ValidateStressMetric(metricName, metricNamespace string, metricDimensions []types.Dimension, metricSampleCount int, startTime, endTime time.Time) error {
+func (s *StressValidator) ValidateStressMetric(ctx context.Context, metricName, metricNamespace string, metricDimensions []types.Dimension, metricSampleCount int, startTime, endTime time.Time) error {
 	var (
 		dataRate       = fmt.Sprint(s.vConfig.GetDataRate())
 		boundAndPeriod = s.vConfig.GetAgentCollectionPeriod().Seconds()
@@ -377,7 +399,7 @@ func (s *StressValidator) ValidateStressMetric(metricName, metricNamespace strin
 	log.Printf("Start to collect and validate metric %s with the namespace %s, start time %v and end time %v \n", metricName, metricNamespace, startTime, endTime)
 
 	// We are only interested in the maximum metric values within the time range
-	metrics, err := awsservice.GetMetricData(stressMetricQueries, startTime, endTime)
+	metrics, err := awsservice.GetMetricDataWithContext(ctx, stressMetricQueries, startTime, endTime)
 	if err != nil {
 		return err
 	}
@@ -386,17 +408,17 @@ func (s *StressValidator) ValidateStressMetric(metricName, metricNamespace strin
 		return fmt.Errorf("\n getting metric %s failed with the namespace %s and dimension %v", metricName, metricNamespace, util.LogCloudWatchDimension(metricDimensions))
 	}
 
+	// Assuming each plugin are testing one at a time
+	// Validate if the corresponding metrics are within the acceptable range [acceptable value +- 30%]
+	metricValue := metrics.MetricDataResults[0].Values[0]
 	if _, ok := metricPluginBoundValue[dataRate][receiver]; !ok {
-		return fmt.Errorf("\n plugin %s does not have data rate", receiver)
+		return fmt.Errorf("\n plugin %s does not have data rate %s, observed metric %s with value %f", receiver, dataRate, metricName, metricValue)
 	}
 
 	if _, ok := metricPluginBoundValue[dataRate][receiver][metricName]; !ok {
-		return fmt.Errorf("\n metric %s does not have bound", receiver)
+		return fmt.Errorf("\n metric %s does not have bound, observed value %f", metricName, metricValue)
 	}
 
-	// Assuming each plugin are testing one at a time
-	// Validate if the corresponding metrics are within the acceptable range [acceptable value +- 30%]
-	metricValue := metrics.MetricDataResults[0].Values[0]
 	upperBoundValue := metricPluginBoundValue[dataRate][receiver][metricName] * (1 + metricErrorBound)
 	log.Printf("Metric %s within the namespace %s has value of %f and the upper bound is %f \n", metricName, metricNamespace, metricValue, upperBoundValue)
 
@@ -406,14 +428,14 @@ func (s *StressValidator) ValidateStressMetric(metricName, metricNamespace strin
 
 	// Validate if the metrics are not dropping any metrics and able to backfill within the same minute (e.g if the memory_rss metric is having collection_interval 1
 	// , it will need to have 60 sample counts - 1 datapoint / second)
-	if ok := awsservice.ValidateSampleCount(metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount-5, metricSampleCount, int32(boundAndPeriod)); !ok {
+	if ok := awsservice.ValidateSampleCountWithContext(ctx, metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount-5, metricSampleCount, int32(boundAndPeriod)); !ok {
 		return fmt.Errorf("\n metric %s is not within sample count bound [ %d, %d]", metricName, metricSampleCount-5, metricSampleCount)
 	}
 
 	return nil
 }
 
-func (s *StressValidator) ValidateStressMetricWindows(metricName, metricNamespace string, metricDimensions []types.Dimension, metricSampleCount int, startTime, endTime time.Time) error {
+func (s *StressValidator) ValidateStressMetricWindows(ctx context.Context, metricName, metricNamespace string, metricDimensions []types.Dimension, metricSampleCount int, startTime, endTime time.Time) error {
 	var (
 		dataRate       = fmt.Sprint(s.vConfig.GetDataRate())
 		boundAndPeriod = s.vConfig.GetAgentCollectionPeriod().Seconds()
@@ -421,7 +443,8 @@ func (s *StressValidator) ValidateStressMetricWindows(metricName, metricNamespac
 	)
 	log.Printf("Start to collect and validate metric %s with the namespace %s, start time %v and end time %v \n", metricName, metricNamespace, startTime, endTime)
 
-	metrics, err := awsservice.GetMetricStatistics(
+	metrics, err := awsservice.GetMetricStatisticsWithContext(
+		ctx,
 		metricName,
 		metricNamespace,
 		metricDimensions,
@@ -459,7 +482,7 @@ func (s *StressValidator) ValidateStressMetricWindows(metricName, metricNamespac
 
 	// Validate if the metrics are not dropping any metrics and able to backfill within the same minute (e.g if the memory_rss metric is having collection_interval 1
 	// , it will need to have 60 sample counts - 1 datapoint / second)
-	if ok := awsservice.ValidateSampleCount(metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount-5, metricSampleCount, int32(boundAndPeriod)); !ok {
+	if ok := awsservice.ValidateSampleCountWithContext(ctx, metricName, metricNamespace, metricDimensions, startTime, endTime, metricSampleCount-5, metricSampleCount, int32(boundAndPeriod)); !ok {
 		return fmt.Errorf("\n metric %s is not within sample count bound [ %d, %d]", metricName, metricSampleCount-5, metricSampleCount)
 	}
 
@@ -4,32 +4,41 @@
 package validators
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"go.uber.org/multierr"
+
+	"github.com/aws/amazon-cloudwatch-agent-test/util/common"
 	"github.com/aws/amazon-cloudwatch-agent-test/validator/models"
-	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/feature"
-	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/performance"
-	"github.com/aws/amazon-cloudwatch-agent-test/validator/validators/stress"
+	// Register the built-in validate types
+	_ "github.com/aws/amazon-cloudwatch-agent-test/validator/validators/feature"
+	_ "github.com/aws/amazon-cloudwatch-agent-test/validator/validators/performance"
+	_ "github.com/aws/amazon-cloudwatch-agent-test/validator/validators/stress"
+)
+
+const (
+	// loadGenerationGracePeriod covers the load generators running a little longer than the agent collection
+	// period (e.g collectd waits 30s before its first flush)
+	loadGenerationGracePeriod = 2 * time.Minute
+	cloudWatchProcessingDelay = 2 * time.Minute
+	checkDataTimeout          = 15 * time.Minute
 )
 
 func NewValidator(vConfig models.ValidateConfig) (validator models.ValidatorFactory, err error) {
-	switch vConfig.GetValidateType() {
-	case "performance":
-		validator = performance.NewPerformanceValidator(vConfig)
-	case "feature":
-		validator = feature.NewFeatureValidator(vConfig)
-	case "stress":
-		validator = stress.NewStressValidator(vConfig)
-	default:
-		return nil, fmt.Errorf("unknown validation type %s provided by test case %s", vConfig.GetValidateType(), vConfig.GetTestCase())
+	registration, err := models.GetValidator(vConfig.GetValidateType())
+	if err != nil {
+		return nil, fmt.Errorf("test case %s: %w", vConfig.GetTestCase(), err)
 	}
 
-	return validator, nil
+	return registration.Factory(vConfig), nil
 }
 
-func LaunchValidator(vConfig models.ValidateConfig) error {
+// LaunchValidator generates the load and checks the data once CloudWatch has processed it. Every phase has its own
+// deadline within the context, and the validator resources are cleaned up even when a phase fails.
+func LaunchValidator(ctx context.Context, vConfig models.ValidateConfig) (err error) {
 	var (
 		agentCollectionPeriod    = vConfig.GetAgentCollectionPeriod()
 		startTimeValidation      = time.Now().Truncate(time.Minute).Add(time.Minute)
@@ -41,30 +50,35 @@ func LaunchValidator(vConfig models.ValidateConfig) error {
 	if err != nil {
 		return err
 	}
-	log.Printf("Start to sleep %f s for the metric to be available in the beginning of next minute ", durationBeforeNextMinute.Seconds())
-	time.Sleep(durationBeforeNextMinute)
+	defer func() {
+		err = multierr.Append(err, validator.Cleanup())
+	}()
 
-	log.Printf("Start to generate load in %f s for the agent to collect and send all the metrics to CloudWatch within the datapoint period ", agentCollectionPeriod.Seconds())
-	err = validator.GenerateLoad()
-	if err != nil {
+	log.Printf("Start to sleep %f s for the metric to be available in the beginning of next minute ", durationBeforeNextMinute.Seconds())
+	if err = common.Sleep(ctx, durationBeforeNextMinute); err != nil {
 		return err
-
 	}
 
-	time.Sleep(agentCollectionPeriod)
-	log.Printf("Start to sleep 120s for CloudWatch to process all the metrics")
-	time.Sleep(2 * time.Minute)
+	log.Printf("Start to generate load in %f s for the agent to collect and send all the metrics to CloudWatch within the datapoint period ", agentCollectionPeriod.Seconds())
+	if err = generateLoad(ctx, validator, agentCollectionPeriod+loadGenerationGracePeriod); err != nil {
+		return fmt.Errorf("failed to generate load: %w", err)
+	}
 
-	err = validator.CheckData(startTimeValidation, endTimeValidation)
-	if err != nil {
+	if err = common.Sleep(ctx, time.Until(endTimeValidation)); err != nil {
 		return err
 	}
-
-	err = validator.Cleanup()
-	if err != nil {
+	log.Printf("Start to sleep %v for CloudWatch to process all the metrics", cloudWatchProcessingDelay)
+	if err = common.Sleep(ctx, cloudWatchProcessingDelay); err != nil {
 		return err
 	}
 
-	return nil
+	checkCtx, cancel := context.WithTimeout(ctx, checkDataTimeout)
+	defer cancel()
+	return validator.CheckData(checkCtx, startTimeValidation, endTimeValidation)
+}
 
+func generateLoad(ctx context.Context, validator models.ValidatorFactory, timeout time.Duration) error {
+	loadCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return validator.GenerateLoad(loadCtx)
 }